@@ -14,32 +14,64 @@ const (
 	ProviderAnthropic ProviderName = "anthropic"
 	// ProviderOpenAI 表示 OpenAI 服务。
 	ProviderOpenAI ProviderName = "openai"
+	// ProviderOllama 表示本地部署的 Ollama 服务（或兼容其 /api/chat 接口的服务）。
+	ProviderOllama ProviderName = "ollama"
+	// ProviderGemini 表示 Google Gemini 服务。
+	ProviderGemini ProviderName = "gemini"
+	// ProviderAzureOpenAI 表示 Azure OpenAI 服务，按部署名访问模型。
+	ProviderAzureOpenAI ProviderName = "azure-openai"
 )
 
+// AzureDefaultAPIVersion 是 Azure OpenAI 的默认 API 版本。
+const AzureDefaultAPIVersion = "2024-10-21"
+
 // Client 是 AI 服务的统一接口。
 type Client interface {
 	// CreateMessage 向 AI 发送 prompt 并返回生成的文本。
 	CreateMessage(ctx context.Context, prompt string) (string, error)
+	// StreamMessage 以流式方式向 AI 发送 prompt，每收到一段生成的文本调用一次 onDelta（可为 nil），
+	// 结束后返回完整文本。ctx 被取消时中断请求并返回 ctx 的错误，同时返回已生成的部分文本。
+	StreamMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error)
 }
 
 // Config 表示 AI 客户端的配置。
 type Config struct {
 	Provider ProviderName // AI 服务提供商（默认: anthropic）
 	APIKey   string       // API 密钥
-	Model    string       // 模型名称（为空时使用 DefaultModel）
+	Model    string       // 模型名称（为空时使用 DefaultModel），azure-openai 忽略该字段
 	BaseURL  string       // API Base URL（为空时使用各 provider 默认值）
+
+	MaxTokens int // 单次生成的最大输出 token 数（为 0 时使用 DefaultMaxTokens）
+
+	Deployment string // Azure OpenAI 部署名（仅 azure-openai）
+	APIVersion string // Azure OpenAI API 版本（仅 azure-openai，为空时使用 AzureDefaultAPIVersion）
 }
 
+// DefaultMaxTokens 是单次生成的默认最大输出 token 数。
+const DefaultMaxTokens = 4096
+
 // DefaultModel 返回指定 provider 的默认模型名称。
+// Azure OpenAI 按部署名（Config.Deployment，即 AZURE_OPENAI_DEPLOYMENT）访问模型，忽略模型名称，返回空字符串。
 func DefaultModel(provider ProviderName) string {
 	switch provider {
+	case ProviderAzureOpenAI:
+		return ""
 	case ProviderOpenAI:
 		return "gpt-4o"
+	case ProviderOllama:
+		return "llama3.1"
+	case ProviderGemini:
+		return "gemini-2.5-flash"
 	default:
 		return "claude-sonnet-4-20250514"
 	}
 }
 
+// RequiresAPIKey 判断指定 provider 是否需要 API Key。本地部署的 Ollama 不需要。
+func RequiresAPIKey(provider ProviderName) bool {
+	return provider != ProviderOllama
+}
+
 // NewClient 根据配置创建对应 provider 的 AI 客户端。
 // 空 provider 默认使用 anthropic。
 func NewClient(cfg Config) (Client, error) {
@@ -49,12 +81,30 @@ func NewClient(cfg Config) (Client, error) {
 	if cfg.Model == "" {
 		cfg.Model = DefaultModel(cfg.Provider)
 	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = DefaultMaxTokens
+	}
 
 	switch cfg.Provider {
 	case ProviderAnthropic:
-		return newAnthropicClient(cfg.APIKey, cfg.Model, cfg.BaseURL), nil
+		return newAnthropicClient(cfg.APIKey, cfg.Model, cfg.BaseURL, cfg.MaxTokens), nil
 	case ProviderOpenAI:
-		return newOpenAIClient(cfg.APIKey, cfg.Model, cfg.BaseURL), nil
+		return newOpenAIClient(cfg.APIKey, cfg.Model, cfg.BaseURL, cfg.MaxTokens), nil
+	case ProviderOllama:
+		return newOllamaClient(cfg.Model, cfg.BaseURL, cfg.MaxTokens), nil
+	case ProviderGemini:
+		return newGeminiClient(cfg.APIKey, cfg.Model, cfg.BaseURL, cfg.MaxTokens), nil
+	case ProviderAzureOpenAI:
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("azure-openai 需要指定资源地址（如 https://<resource>.openai.azure.com）")
+		}
+		if cfg.Deployment == "" {
+			return nil, fmt.Errorf("azure-openai 需要指定部署名")
+		}
+		if cfg.APIVersion == "" {
+			cfg.APIVersion = AzureDefaultAPIVersion
+		}
+		return newAzureOpenAIClient(cfg.APIKey, cfg.BaseURL, cfg.Deployment, cfg.APIVersion, cfg.MaxTokens), nil
 	default:
 		return nil, fmt.Errorf("不支持的 AI 服务提供商: %s", cfg.Provider)
 	}
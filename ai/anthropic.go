@@ -7,37 +7,41 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 const anthropicDefaultBaseURL = "https://api.anthropic.com"
 
 // anthropicClient 是 Anthropic Messages API 客户端。
 type anthropicClient struct {
-	apiKey  string
-	baseURL string
-	model   string
-	http    *http.Client
+	apiKey    string
+	baseURL   string
+	model     string
+	maxTokens int
+	http      *http.Client
 }
 
 // newAnthropicClient 创建一个新的 Anthropic API 客户端。
 // baseURL 为空时使用默认值 https://api.anthropic.com。
-func newAnthropicClient(apiKey, model, baseURL string) *anthropicClient {
+func newAnthropicClient(apiKey, model, baseURL string, maxTokens int) *anthropicClient {
 	if baseURL == "" {
 		baseURL = anthropicDefaultBaseURL
 	}
 	return &anthropicClient{
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		model:   model,
-		http:    &http.Client{},
+		apiKey:    apiKey,
+		baseURL:   baseURL,
+		model:     model,
+		maxTokens: maxTokens,
+		http:      &http.Client{},
 	}
 }
 
 // anthropicMessage 表示 Messages API 的请求体。
 type anthropicMessage struct {
-	Model     string               `json:"model"`
-	MaxTokens int                  `json:"max_tokens"`
-	Messages  []anthropicMsgItem   `json:"messages"`
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMsgItem `json:"messages"`
+	Stream    bool               `json:"stream,omitempty"`
 }
 
 // anthropicMsgItem 表示对话中的单条消息。
@@ -64,33 +68,21 @@ type anthropicAPIError struct {
 	Message string `json:"message"`
 }
 
+// anthropicStreamEvent 表示流式响应中的事件数据，只解析生成文本和错误所需的字段。
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *anthropicAPIError `json:"error,omitempty"`
+}
+
 // CreateMessage 向 Claude 发送 prompt 并返回生成的文本。
 func (c *anthropicClient) CreateMessage(ctx context.Context, prompt string) (string, error) {
-	body := anthropicMessage{
-		Model:     c.model,
-		MaxTokens: 4096,
-		Messages: []anthropicMsgItem{
-			{Role: "user", Content: prompt},
-		},
-	}
-
-	payload, err := json.Marshal(body)
-	if err != nil {
-		return "", fmt.Errorf("marshaling request: %w", err)
-	}
-
-	url := c.baseURL + "/v1/messages"
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
-	resp, err := c.http.Do(req)
+	resp, err := c.send(ctx, prompt, false)
 	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -99,10 +91,6 @@ func (c *anthropicClient) CreateMessage(ctx context.Context, prompt string) (str
 		return "", fmt.Errorf("reading response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
-	}
-
 	var result anthropicResponse
 	if err := json.Unmarshal(respBody, &result); err != nil {
 		return "", fmt.Errorf("parsing response: %w", err)
@@ -122,3 +110,86 @@ func (c *anthropicClient) CreateMessage(ctx context.Context, prompt string) (str
 
 	return text, nil
 }
+
+// StreamMessage 以流式方式（SSE）向 Claude 发送 prompt，每收到一段文本调用一次 onDelta，
+// 收到 message_stop 后返回完整文本；在此之前连接关闭时返回已收到的文本和 errStreamIncomplete。
+func (c *anthropicClient) StreamMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	resp, err := c.send(ctx, prompt, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	err = readSSE(resp.Body, func(_, data string) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("parsing stream event: %w", err)
+		}
+		switch event.Type {
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+				text.WriteString(event.Delta.Text)
+				if onDelta != nil {
+					onDelta(event.Delta.Text)
+				}
+			}
+		case "message_stop":
+			return errStreamDone
+		case "error":
+			if event.Error != nil {
+				return fmt.Errorf("API error (%s): %s", event.Error.Type, event.Error.Message)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = errStreamIncomplete
+	}
+	if err = streamError(ctx, err); err != nil {
+		return text.String(), err
+	}
+
+	return text.String(), nil
+}
+
+// send 发送 Messages API 请求，返回状态码为 200 的响应，调用方负责关闭响应体。
+func (c *anthropicClient) send(ctx context.Context, prompt string, stream bool) (*http.Response, error) {
+	body := anthropicMessage{
+		Model:     c.model,
+		MaxTokens: c.maxTokens,
+		Messages: []anthropicMsgItem{
+			{Role: "user", Content: prompt},
+		},
+		Stream: stream,
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	url := c.baseURL + "/v1/messages"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,272 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const geminiDefaultBaseURL = "https://generativelanguage.googleapis.com"
+
+// geminiClient 是 Google Gemini generateContent API 客户端。
+type geminiClient struct {
+	apiKey    string
+	baseURL   string
+	model     string
+	maxTokens int
+	http      *http.Client
+}
+
+// newGeminiClient 创建一个新的 Gemini API 客户端。
+// baseURL 为空时使用默认值 https://generativelanguage.googleapis.com。
+func newGeminiClient(apiKey, model, baseURL string, maxTokens int) *geminiClient {
+	if baseURL == "" {
+		baseURL = geminiDefaultBaseURL
+	}
+	return &geminiClient{
+		apiKey:    apiKey,
+		baseURL:   baseURL,
+		model:     model,
+		maxTokens: maxTokens,
+		http:      &http.Client{},
+	}
+}
+
+// geminiRequest 表示 generateContent API 的请求体。
+type geminiRequest struct {
+	Contents         []geminiContent        `json:"contents"`
+	GenerationConfig geminiGenerationConfig `json:"generationConfig"`
+}
+
+// geminiContent 表示对话中的单条消息，由多个 part 组成。
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+// geminiPart 表示消息中的一个文本片段。
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+// geminiGenerationConfig 表示生成参数。
+type geminiGenerationConfig struct {
+	MaxOutputTokens int `json:"maxOutputTokens,omitempty"`
+}
+
+// geminiResponse 表示 generateContent API 的响应体，流式响应的每个事件也是该结构。
+type geminiResponse struct {
+	Candidates     []geminiCandidate     `json:"candidates"`
+	PromptFeedback *geminiPromptFeedback `json:"promptFeedback,omitempty"`
+	Error          *geminiError          `json:"error,omitempty"`
+}
+
+// geminiCandidate 表示响应中的一个候选结果。
+type geminiCandidate struct {
+	Content       geminiContent        `json:"content"`
+	FinishReason  string               `json:"finishReason"`
+	SafetyRatings []geminiSafetyRating `json:"safetyRatings"`
+}
+
+// geminiPromptFeedback 表示对 prompt 的安全审查结果，blockReason 非空时 prompt 被拦截。
+type geminiPromptFeedback struct {
+	BlockReason   string               `json:"blockReason"`
+	SafetyRatings []geminiSafetyRating `json:"safetyRatings"`
+}
+
+// geminiSafetyRating 表示单个安全类别的评估结果。
+type geminiSafetyRating struct {
+	Category    string `json:"category"`
+	Probability string `json:"probability"`
+	Blocked     bool   `json:"blocked"`
+}
+
+// geminiError 表示 API 返回的错误。
+type geminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// geminiBlockedReasons 是因内容审查而中止生成的 finishReason。
+var geminiBlockedReasons = map[string]bool{
+	"SAFETY":             true,
+	"RECITATION":         true,
+	"BLOCKLIST":          true,
+	"PROHIBITED_CONTENT": true,
+	"SPII":               true,
+}
+
+// text 拼接第一个候选结果（请求未指定 candidateCount，只会有一个）中的文本 part，并检查错误和安全拦截。
+// finishReason 为该候选结果的结束原因，没有候选结果时为空。
+func (r geminiResponse) text() (text, finishReason string, err error) {
+	if r.Error != nil {
+		return "", "", fmt.Errorf("API error (%s): %s", r.Error.Status, r.Error.Message)
+	}
+	if r.PromptFeedback != nil && r.PromptFeedback.BlockReason != "" {
+		return "", "", fmt.Errorf("prompt blocked by Gemini safety filters (%s%s)",
+			r.PromptFeedback.BlockReason, blockedCategories(r.PromptFeedback.SafetyRatings))
+	}
+	if len(r.Candidates) == 0 {
+		return "", "", nil
+	}
+
+	candidate := r.Candidates[0]
+	for _, part := range candidate.Content.Parts {
+		text += part.Text
+	}
+	if geminiBlockedReasons[candidate.FinishReason] {
+		return text, candidate.FinishReason, fmt.Errorf("response blocked by Gemini safety filters (%s%s)",
+			candidate.FinishReason, blockedCategories(candidate.SafetyRatings))
+	}
+	return text, candidate.FinishReason, nil
+}
+
+// emptyResponseError 返回没有生成任何文本时的错误，finishReason 为空表示响应中没有候选结果。
+func emptyResponseError(finishReason string) error {
+	if finishReason == "" {
+		return errors.New("no candidates in Gemini response")
+	}
+	return fmt.Errorf("empty response from Gemini (finishReason: %s)", finishReason)
+}
+
+// blockedCategories 返回被拦截（或风险为 HIGH）的安全类别，格式为 ": HARM_CATEGORY_X, HARM_CATEGORY_Y"，没有时为空。
+func blockedCategories(ratings []geminiSafetyRating) string {
+	var categories []string
+	for _, r := range ratings {
+		if r.Blocked || r.Probability == "HIGH" {
+			categories = append(categories, r.Category)
+		}
+	}
+	if len(categories) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(categories, ", ")
+}
+
+// CreateMessage 向 Gemini 发送 prompt 并返回生成的文本。
+func (c *geminiClient) CreateMessage(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.send(ctx, prompt, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	var result geminiResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+
+	text, finishReason, err := result.text()
+	if err != nil {
+		return text, err
+	}
+	if text == "" {
+		return "", emptyResponseError(finishReason)
+	}
+	return text, nil
+}
+
+// StreamMessage 以流式方式（SSE）向 Gemini 发送 prompt，每收到一段文本调用一次 onDelta，
+// 收到带 finishReason 的事件后返回完整文本；在此之前连接关闭时返回已收到的文本和 errStreamIncomplete。
+// 单个事件可能没有文本，整个流都没有生成文本时返回错误。
+func (c *geminiClient) StreamMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	resp, err := c.send(ctx, prompt, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	var finishReason string
+	err = readSSE(resp.Body, func(_, data string) error {
+		var chunk geminiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("parsing stream chunk: %w", err)
+		}
+		delta, reason, err := chunk.text()
+		if reason != "" {
+			finishReason = reason
+		}
+		if delta != "" {
+			text.WriteString(delta)
+			if onDelta != nil {
+				onDelta(delta)
+			}
+		}
+		return err
+	})
+	if err == nil && finishReason == "" {
+		err = errStreamIncomplete
+	}
+	if err = streamError(ctx, err); err != nil {
+		return text.String(), err
+	}
+	if text.Len() == 0 {
+		return "", emptyResponseError(finishReason)
+	}
+
+	return text.String(), nil
+}
+
+// send 发送 generateContent（流式时为 streamGenerateContent）请求，返回状态码为 200 的响应，
+// 调用方负责关闭响应体。API Key 通过 x-goog-api-key 请求头传递。
+func (c *geminiClient) send(ctx context.Context, prompt string, stream bool) (*http.Response, error) {
+	body := geminiRequest{
+		Contents: []geminiContent{
+			{Role: "user", Parts: []geminiPart{{Text: prompt}}},
+		},
+		GenerationConfig: geminiGenerationConfig{MaxOutputTokens: c.maxTokens},
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	// 兼容 base URL 末尾带版本号（/v1beta 或 /v1）的情况
+	base := strings.TrimRight(c.baseURL, "/")
+	if !strings.HasSuffix(base, "/v1beta") && !strings.HasSuffix(base, "/v1") {
+		base += "/v1beta"
+	}
+	endpoint := base + "/models/" + url.PathEscape(c.model)
+	if stream {
+		endpoint += ":streamGenerateContent?alt=sse"
+	} else {
+		endpoint += ":generateContent"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		// 优先返回错误体中的可读信息
+		var result geminiResponse
+		if json.Unmarshal(respBody, &result) == nil && result.Error != nil {
+			return nil, fmt.Errorf("API returned status %d (%s): %s", resp.StatusCode, result.Error.Status, result.Error.Message)
+		}
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const ollamaDefaultBaseURL = "http://localhost:11434"
+
+// ollamaClient 是 Ollama Chat API（/api/chat）客户端，用于本地部署的模型，不需要 API Key。
+type ollamaClient struct {
+	baseURL   string
+	model     string
+	maxTokens int
+	http      *http.Client
+}
+
+// newOllamaClient 创建一个新的 Ollama API 客户端。
+// baseURL 为空时使用默认值 http://localhost:11434，不带协议时（如 OLLAMA_HOST 的 "127.0.0.1:11434"）补充 http://。
+func newOllamaClient(model, baseURL string, maxTokens int) *ollamaClient {
+	if baseURL == "" {
+		baseURL = ollamaDefaultBaseURL
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+	return &ollamaClient{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		model:     model,
+		maxTokens: maxTokens,
+		http:      &http.Client{},
+	}
+}
+
+// ollamaRequest 表示 Chat API 的请求体。
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  ollamaOptions   `json:"options"`
+}
+
+// ollamaMessage 表示对话中的单条消息。
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ollamaOptions 表示模型参数。
+type ollamaOptions struct {
+	NumPredict int `json:"num_predict,omitempty"` // 最大输出 token 数
+}
+
+// ollamaResponse 表示 Chat API 的响应体。流式响应为每行一个的 JSON 对象，最后一个对象的 done 为 true。
+type ollamaResponse struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// CreateMessage 向 Ollama 发送 prompt 并返回生成的文本。
+func (c *ollamaClient) CreateMessage(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.send(ctx, prompt, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	var result ollamaResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+
+	if result.Error != "" {
+		return "", fmt.Errorf("API error: %s", result.Error)
+	}
+
+	return result.Message.Content, nil
+}
+
+// StreamMessage 以流式方式向 Ollama 发送 prompt，每收到一段文本调用一次 onDelta，
+// 收到 done 为 true 的响应后返回完整文本；在此之前连接关闭时返回已收到的文本和 errStreamIncomplete。
+// Ollama 的流式响应为逐行的 JSON（NDJSON），而非 SSE。
+func (c *ollamaClient) StreamMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	resp, err := c.send(ctx, prompt, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var chunk ollamaResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return text.String(), fmt.Errorf("parsing stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return text.String(), fmt.Errorf("API error: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			text.WriteString(chunk.Message.Content)
+			if onDelta != nil {
+				onDelta(chunk.Message.Content)
+			}
+		}
+		if chunk.Done {
+			return text.String(), nil
+		}
+	}
+	err = scanner.Err()
+	if err == nil {
+		err = errStreamIncomplete
+	}
+	return text.String(), streamError(ctx, err)
+}
+
+// send 发送 Chat API 请求，返回状态码为 200 的响应，调用方负责关闭响应体。
+func (c *ollamaClient) send(ctx context.Context, prompt string, stream bool) (*http.Response, error) {
+	body := ollamaRequest{
+		Model: c.model,
+		Messages: []ollamaMessage{
+			{Role: "user", Content: prompt},
+		},
+		Stream:  stream,
+		Options: ollamaOptions{NumPredict: c.maxTokens},
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/chat", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,186 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newOllamaTestServer 启动一个模拟 /api/chat 的服务器，按请求的 stream 字段返回 body，并检查请求体。
+func newOllamaTestServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		if req.Model != "llama3" || req.Options.NumPredict != 128 {
+			t.Errorf("unexpected request body: %+v", req)
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Role != "user" || req.Messages[0].Content != "hello" {
+			t.Errorf("unexpected messages: %+v", req.Messages)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOllamaCreateMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "completion",
+			status: http.StatusOK,
+			body:   `{"message":{"role":"assistant","content":"本周工作"},"done":true}`,
+			want:   "本周工作",
+		},
+		{
+			name:    "error body",
+			status:  http.StatusOK,
+			body:    `{"error":"model \"llama3\" not found"}`,
+			wantErr: `API error: model "llama3" not found`,
+		},
+		{
+			name:    "error status",
+			status:  http.StatusNotFound,
+			body:    `{"error":"model \"llama3\" not found, try pulling it first"}`,
+			wantErr: "API returned status 404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newOllamaTestServer(t, tt.status, tt.body)
+			client := newOllamaClient("llama3", srv.URL, 128)
+
+			got, err := client.CreateMessage(context.Background(), "hello")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreateMessage() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateMessage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CreateMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOllamaStreamMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		want       string
+		wantDeltas []string
+		wantErr    string
+	}{
+		{
+			name:   "completion",
+			status: http.StatusOK,
+			body: `{"message":{"role":"assistant","content":"本周"},"done":false}` + "\n" +
+				`{"message":{"role":"assistant","content":"工作"},"done":false}` + "\n" +
+				"\n" +
+				`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop"}` + "\n",
+			want:       "本周工作",
+			wantDeltas: []string{"本周", "工作"},
+		},
+		{
+			name:   "stops at done",
+			status: http.StatusOK,
+			body: `{"message":{"role":"assistant","content":"完成"},"done":true}` + "\n" +
+				`{"message":{"role":"assistant","content":"多余"},"done":false}` + "\n",
+			want:       "完成",
+			wantDeltas: []string{"完成"},
+		},
+		{
+			name:   "without done",
+			status: http.StatusOK,
+			body:   `{"message":{"role":"assistant","content":"部分"},"done":false}`,
+			// 连接在 done 之前关闭时返回已收到的文本和错误
+			want:       "部分",
+			wantDeltas: []string{"部分"},
+			wantErr:    "stream ended before completion",
+		},
+		{
+			name:   "error body",
+			status: http.StatusOK,
+			body: `{"message":{"role":"assistant","content":"部分"},"done":false}` + "\n" +
+				`{"error":"out of memory"}` + "\n",
+			want:       "部分",
+			wantDeltas: []string{"部分"},
+			wantErr:    "API error: out of memory",
+		},
+		{
+			name:    "invalid chunk",
+			status:  http.StatusOK,
+			body:    "not json\n",
+			wantErr: "parsing stream chunk",
+		},
+		{
+			name:    "error status",
+			status:  http.StatusInternalServerError,
+			body:    `{"error":"server busy"}`,
+			wantErr: "API returned status 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newOllamaTestServer(t, tt.status, tt.body)
+			client := newOllamaClient("llama3", srv.URL, 128)
+
+			var deltas []string
+			got, err := client.StreamMessage(context.Background(), "hello", func(d string) {
+				deltas = append(deltas, d)
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("StreamMessage() error = %v, want containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("StreamMessage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("StreamMessage() = %q, want %q", got, tt.want)
+			}
+			if strings.Join(deltas, "|") != strings.Join(tt.wantDeltas, "|") {
+				t.Errorf("deltas = %q, want %q", deltas, tt.wantDeltas)
+			}
+		})
+	}
+}
+
+func TestNewOllamaClientBaseURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"", "http://localhost:11434"},
+		{"127.0.0.1:11434", "http://127.0.0.1:11434"},
+		{"https://ollama.example.com/", "https://ollama.example.com"},
+	}
+	for _, tt := range tests {
+		if got := newOllamaClient("llama3", tt.baseURL, 0).baseURL; got != tt.want {
+			t.Errorf("newOllamaClient(%q).baseURL = %q, want %q", tt.baseURL, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -14,30 +15,51 @@ const openaiDefaultBaseURL = "https://api.openai.com"
 
 // openaiClient 是 OpenAI Chat Completions API 客户端。
 type openaiClient struct {
-	apiKey  string
-	baseURL string
-	model   string
-	http    *http.Client
+	apiKey    string
+	baseURL   string
+	model     string
+	maxTokens int
+	http      *http.Client
+
+	// Azure OpenAI：deployment 非空时按部署名构建 URL，并通过 api-key 请求头认证
+	deployment string
+	apiVersion string
 }
 
 // newOpenAIClient 创建一个新的 OpenAI API 客户端。
 // baseURL 为空时使用默认值 https://api.openai.com。
-func newOpenAIClient(apiKey, model, baseURL string) *openaiClient {
+func newOpenAIClient(apiKey, model, baseURL string, maxTokens int) *openaiClient {
 	if baseURL == "" {
 		baseURL = openaiDefaultBaseURL
 	}
 	return &openaiClient{
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		model:   model,
-		http:    &http.Client{},
+		apiKey:    apiKey,
+		baseURL:   baseURL,
+		model:     model,
+		maxTokens: maxTokens,
+		http:      &http.Client{},
+	}
+}
+
+// newAzureOpenAIClient 创建一个新的 Azure OpenAI 客户端。
+// baseURL 为资源地址（如 https://<resource>.openai.azure.com），请求发送到部署名对应的 Chat Completions 端点。
+func newAzureOpenAIClient(apiKey, baseURL, deployment, apiVersion string, maxTokens int) *openaiClient {
+	return &openaiClient{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		maxTokens:  maxTokens,
+		http:       &http.Client{},
+		deployment: deployment,
+		apiVersion: apiVersion,
 	}
 }
 
 // openaiRequest 表示 Chat Completions API 的请求体。
 type openaiRequest struct {
-	Model    string          `json:"model"`
-	Messages []openaiMessage `json:"messages"`
+	Model     string          `json:"model,omitempty"` // Azure OpenAI 按部署名选择模型，不发送
+	Messages  []openaiMessage `json:"messages"`
+	MaxTokens int             `json:"max_tokens,omitempty"`
+	Stream    bool            `json:"stream,omitempty"`
 }
 
 // openaiMessage 表示对话中的单条消息。
@@ -63,61 +85,143 @@ type openaiError struct {
 	Type    string `json:"type"`
 }
 
+// openaiStreamChunk 表示流式响应中的一个数据块。
+type openaiStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *openaiError `json:"error,omitempty"`
+}
+
 // CreateMessage 向 OpenAI 发送 prompt 并返回生成的文本。
 func (c *openaiClient) CreateMessage(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.send(ctx, prompt, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	var result openaiResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+
+	if result.Error != nil {
+		return "", fmt.Errorf("API error (%s): %s", result.Error.Type, result.Error.Message)
+	}
+
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("API returned empty choices")
+	}
+
+	return result.Choices[0].Message.Content, nil
+}
+
+// StreamMessage 以流式方式（SSE）向 OpenAI 发送 prompt，每收到一段文本调用一次 onDelta，
+// 收到 "[DONE]" 后返回完整文本；在此之前连接关闭时返回已收到的文本和 errStreamIncomplete。
+func (c *openaiClient) StreamMessage(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	resp, err := c.send(ctx, prompt, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	err = readSSE(resp.Body, func(_, data string) error {
+		if data == "[DONE]" {
+			return errStreamDone
+		}
+		var chunk openaiStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("parsing stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("API error (%s): %s", chunk.Error.Type, chunk.Error.Message)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			text.WriteString(choice.Delta.Content)
+			if onDelta != nil {
+				onDelta(choice.Delta.Content)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = errStreamIncomplete
+	}
+	if err = streamError(ctx, err); err != nil {
+		return text.String(), err
+	}
+
+	return text.String(), nil
+}
+
+// send 发送 Chat Completions API 请求，返回状态码为 200 的响应，调用方负责关闭响应体。
+func (c *openaiClient) send(ctx context.Context, prompt string, stream bool) (*http.Response, error) {
 	body := openaiRequest{
 		Model: c.model,
 		Messages: []openaiMessage{
 			{Role: "user", Content: prompt},
 		},
+		MaxTokens: c.maxTokens,
+		Stream:    stream,
 	}
 
 	payload, err := json.Marshal(body)
 	if err != nil {
-		return "", fmt.Errorf("marshaling request: %w", err)
+		return nil, fmt.Errorf("marshaling request: %w", err)
 	}
 
-	// 兼容 base URL 末尾带 /v1 的情况（OpenAI SDK 惯例）
-	base := strings.TrimRight(c.baseURL, "/")
-	if strings.HasSuffix(base, "/v1") {
-		base = base + "/chat/completions"
-	} else {
-		base = base + "/v1/chat/completions"
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base, bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(), bytes.NewReader(payload))
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+	if c.deployment != "" {
+		req.Header.Set("api-key", c.apiKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	resp, err := c.http.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("reading response: %w", err)
+		return nil, fmt.Errorf("sending request: %w", err)
 	}
-
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	var result openaiResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return "", fmt.Errorf("parsing response: %w", err)
-	}
+	return resp, nil
+}
 
-	if result.Error != nil {
-		return "", fmt.Errorf("API error (%s): %s", result.Error.Type, result.Error.Message)
+// endpoint 返回 Chat Completions 端点的 URL。
+// Azure OpenAI 为 {base}/openai/deployments/{deployment}/chat/completions?api-version=...。
+func (c *openaiClient) endpoint() string {
+	base := strings.TrimRight(c.baseURL, "/")
+	if c.deployment != "" {
+		base = strings.TrimSuffix(base, "/openai")
+		return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			base, url.PathEscape(c.deployment), url.QueryEscape(c.apiVersion))
 	}
 
-	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("API returned empty choices")
+	// 兼容 base URL 末尾带 /v1 的情况（OpenAI SDK 惯例）
+	if strings.HasSuffix(base, "/v1") {
+		return base + "/chat/completions"
 	}
-
-	return result.Choices[0].Message.Content, nil
+	return base + "/v1/chat/completions"
 }
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxSSELineSize 是 SSE 单行的最大长度，单个事件的 JSON 数据可能超过 bufio 默认的 64KB。
+const maxSSELineSize = 1 << 20
+
+// readSSE 逐个读取 Server-Sent Events 流中的事件，对每个事件调用 fn（event 为空时表示未指定事件类型）。
+// 多行 data 按规范以换行拼接，注释行（以 ":" 开头）和其他字段被忽略。fn 返回错误时停止读取并返回该错误。
+func readSSE(r io.Reader, fn func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+
+	var event string
+	var data []string
+	dispatch := func() error {
+		if len(data) == 0 {
+			event = ""
+			return nil
+		}
+		err := fn(event, strings.Join(data, "\n"))
+		event, data = "", nil
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := dispatch(); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// 流结束时分发最后一个未以空行结尾的事件
+	return dispatch()
+}
+
+// errStreamDone 表示流已正常结束（如 OpenAI 的 "[DONE]" 事件），用于提前停止读取。
+var errStreamDone = errors.New("stream done")
+
+// errStreamIncomplete 表示连接在收到结束标记（如 "[DONE]"、message_stop）之前关闭，已收到的文本不完整。
+var errStreamIncomplete = errors.New("stream ended before completion")
+
+// streamError 转换读取流时的错误：流正常结束时返回 nil；请求被取消（如 Ctrl-C）时返回 ctx 的错误，
+// 便于调用方通过 errors.Is(err, context.Canceled) 区分。
+func streamError(ctx context.Context, err error) error {
+	switch {
+	case err == nil, errors.Is(err, errStreamDone):
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	default:
+		return fmt.Errorf("reading stream: %w", err)
+	}
+}
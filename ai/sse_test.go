@@ -0,0 +1,203 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseEvent 是 readSSE 回调收到的一个事件。
+type sseEvent struct {
+	event string
+	data  string
+}
+
+func TestReadSSE(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		stopAt  string // 回调收到该 data 时返回 errStreamDone
+		want    []sseEvent
+		wantErr error
+	}{
+		{
+			name:  "single events",
+			input: "data: a\n\ndata: b\n\n",
+			want:  []sseEvent{{data: "a"}, {data: "b"}},
+		},
+		{
+			name:  "event type",
+			input: "event: message_start\ndata: {}\n\nevent: content_block_delta\ndata: {\"x\":1}\n\n",
+			want:  []sseEvent{{event: "message_start", data: "{}"}, {event: "content_block_delta", data: `{"x":1}`}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: line1\ndata: line2\ndata:line3\n\n",
+			want:  []sseEvent{{data: "line1\nline2\nline3"}},
+		},
+		{
+			name:  "comments and unknown fields",
+			input: ": keep-alive\nid: 1\nretry: 1000\ndata: a\n: another comment\n\n:ping\n\n",
+			want:  []sseEvent{{data: "a"}},
+		},
+		{
+			name:  "event without data",
+			input: "event: ping\n\ndata: a\n\n",
+			want:  []sseEvent{{data: "a"}},
+		},
+		{
+			name:    "done",
+			input:   "data: a\n\ndata: [DONE]\n\ndata: b\n\n",
+			stopAt:  "[DONE]",
+			want:    []sseEvent{{data: "a"}, {data: "[DONE]"}},
+			wantErr: errStreamDone,
+		},
+		{
+			name:  "missing trailing blank line",
+			input: "data: a\n\ndata: b",
+			want:  []sseEvent{{data: "a"}, {data: "b"}},
+		},
+		{
+			name:  "empty stream",
+			input: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []sseEvent
+			err := readSSE(strings.NewReader(tt.input), func(event, data string) error {
+				got = append(got, sseEvent{event: event, data: data})
+				if tt.stopAt != "" && data == tt.stopAt {
+					return errStreamDone
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readSSE() error = %v, want %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("readSSE() events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamErrorDone(t *testing.T) {
+	if err := streamError(context.Background(), errStreamDone); err != nil {
+		t.Errorf("streamError(errStreamDone) = %v, want nil", err)
+	}
+	if err := streamError(context.Background(), nil); err != nil {
+		t.Errorf("streamError(nil) = %v, want nil", err)
+	}
+}
+
+// TestStreamMessageCancel 在流式响应中途取消 context，StreamMessage 应返回 context.Canceled 和已收到的文本。
+func TestStreamMessageCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"部分\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		// 不再发送数据，直到客户端断开连接
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := newOpenAIClient("sk-test", "gpt-4o", srv.URL, 128)
+
+	done := make(chan struct{})
+	var got string
+	var err error
+	go func() {
+		defer close(done)
+		got, err = client.StreamMessage(ctx, "hello", func(string) { cancel() })
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("StreamMessage() did not return after context cancellation")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamMessage() error = %v, want context.Canceled", err)
+	}
+	if got != "部分" {
+		t.Errorf("StreamMessage() = %q, want %q", got, "部分")
+	}
+}
+
+// TestStreamMessageIncomplete 在收到结束标记之前关闭连接，StreamMessage 应返回已收到的文本和 errStreamIncomplete。
+func TestStreamMessageIncomplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		client func(baseURL string) Client
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "openai done",
+			body:   "data: {\"choices\":[{\"delta\":{\"content\":\"完整\"}}]}\n\ndata: [DONE]\n\n",
+			client: func(u string) Client { return newOpenAIClient("sk-test", "gpt-4o", u, 128) },
+			want:   "完整",
+			wantOK: true,
+		},
+		{
+			name:   "openai truncated",
+			body:   "data: {\"choices\":[{\"delta\":{\"content\":\"部分\"}}]}\n\n",
+			client: func(u string) Client { return newOpenAIClient("sk-test", "gpt-4o", u, 128) },
+			want:   "部分",
+		},
+		{
+			name: "anthropic stop",
+			body: "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"完整\"}}\n\n" +
+				"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+			client: func(u string) Client { return newAnthropicClient("sk-test", "claude", u, 128) },
+			want:   "完整",
+			wantOK: true,
+		},
+		{
+			name:   "anthropic truncated",
+			body:   "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"部分\"}}\n\n",
+			client: func(u string) Client { return newAnthropicClient("sk-test", "claude", u, 128) },
+			want:   "部分",
+		},
+		{
+			name:   "gemini truncated",
+			body:   "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"部分\"}]}}]}\n\n",
+			client: func(u string) Client { return newGeminiClient("key", "gemini-2.5-flash", u, 128) },
+			want:   "部分",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			got, err := tt.client(srv.URL).StreamMessage(context.Background(), "hello", nil)
+			if tt.wantOK && err != nil {
+				t.Fatalf("StreamMessage() error = %v", err)
+			}
+			if !tt.wantOK && !errors.Is(err, errStreamIncomplete) {
+				t.Fatalf("StreamMessage() error = %v, want errStreamIncomplete", err)
+			}
+			if got != tt.want {
+				t.Errorf("StreamMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/miclle/gh-report/ai"
+	"github.com/miclle/gh-report/report"
+	"github.com/miclle/gh-report/ui"
+)
+
+// generateAIReport 按生成计划调用 AI 生成报告并输出到标准输出。
+// 计划分段时先逐段总结（显示 Spinner），合并 Prompt 仍超出上限时分组逐轮合并，最后以流式方式生成最终报告。
+func generateAIReport(ctx context.Context, client ai.Client, plan report.SummaryPlan, provider ai.ProviderName, reportName string) error {
+	merged := 0
+	for round := 1; len(plan.Chunks) > 0; round++ {
+		partials := make([]string, len(plan.Chunks))
+		for i, chunk := range plan.Chunks {
+			text := fmt.Sprintf("正在调用 %s API 总结第 %d/%d 段（%s）...", provider, i+1, len(plan.Chunks), chunk.Label)
+			if round > 1 {
+				text = fmt.Sprintf("正在调用 %s API 合并第 %d/%d 组总结（第 %d 轮）...", provider, i+1, len(plan.Chunks), round)
+			}
+			partial, err := ui.RunSpinnerWithResult(text, func() (string, error) {
+				return client.CreateMessage(ctx, chunk.Prompt)
+			})
+			if err != nil {
+				return fmt.Errorf("总结第 %d 段（%s）: %w", i+1, chunk.Label, err)
+			}
+			partials[i] = partial
+		}
+		merged = len(plan.Chunks)
+		plan = plan.Merge(partials)
+	}
+
+	// 以流式方式调用 AI API，生成前显示 Spinner
+	spinnerText := fmt.Sprintf("正在调用 %s API 生成%s...", provider, reportName)
+	if merged > 0 {
+		spinnerText = fmt.Sprintf("正在调用 %s API 合并 %d 段总结，生成%s...", provider, merged, reportName)
+	}
+	prompt := plan.Prompt
+	// 标准输出为终端时实时输出生成的文本；重定向到文件时生成完成后一次性写入
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		_, err := ui.StreamWithSpinner(spinnerText, os.Stdout, func(onDelta func(string)) (string, error) {
+			return client.StreamMessage(ctx, prompt, onDelta)
+		})
+		return err
+	}
+	result, err := ui.RunSpinnerWithResult(spinnerText, func() (string, error) {
+		return client.StreamMessage(ctx, prompt, nil)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout, result)
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miclle/gh-report/github"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "管理 GitHub API 的 HTTP 缓存",
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "显示 HTTP 缓存的条目数和占用空间",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cacheDirFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		stats, err := github.ReadCacheStats(dir)
+		if err != nil {
+			return fmt.Errorf("读取缓存目录失败: %w", err)
+		}
+
+		fmt.Printf("目录:   %s\n", dir)
+		fmt.Printf("条目数: %d\n", stats.Entries)
+		fmt.Printf("大小:   %s\n", formatBytes(stats.Bytes))
+		if stats.Entries > 0 {
+			fmt.Printf("最早:   %s\n", stats.Oldest.Format("2006-01-02 15:04:05"))
+			fmt.Printf("最近:   %s\n", stats.Newest.Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "清空 HTTP 缓存（只删除缓存自身的文件）",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cacheDirFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if err := github.ClearCache(dir); err != nil {
+			return fmt.Errorf("清空缓存失败: %w", err)
+		}
+		fmt.Printf("已清空缓存: %s\n", dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// cacheDirFromFlags 读取 --config 指定的配置文件（如有），返回缓存目录。
+func cacheDirFromFlags(cmd *cobra.Command) (string, error) {
+	var cfg Config
+	configFile, _ := cmd.Flags().GetString("config")
+	if configFile != "" {
+		c, err := LoadConfig(configFile)
+		if err != nil {
+			return "", err
+		}
+		cfg = *c
+	}
+	return resolveCacheDir(&cfg)
+}
+
+// resolveCacheDir 返回 HTTP 缓存目录：配置文件 cache_dir > 默认目录。
+func resolveCacheDir(cfg *Config) (string, error) {
+	if cfg.CacheDir != "" {
+		return cfg.CacheDir, nil
+	}
+	dir, err := github.DefaultCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("无法确定缓存目录（可在配置文件中指定 cache_dir）: %w", err)
+	}
+	return dir, nil
+}
+
+// formatBytes 将字节数格式化为易读的形式。
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
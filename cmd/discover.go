@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/miclle/gh-report/github"
+)
+
+// discoverOrgRepos 通过 Search API 查找 logins 中任一用户在 [since, until] 内于 cfg.Org 下有活动的仓库，
+// 按 repo_include / repo_exclude 过滤后返回。
+func discoverOrgRepos(ctx context.Context, client *github.Client, cfg *Config, logins []string, since, until time.Time) ([]string, error) {
+	if len(logins) == 0 {
+		return nil, fmt.Errorf("org 模式需要指定用户（user、users 或 team）")
+	}
+
+	var repos []string
+	seen := make(map[string]bool)
+	for _, login := range logins {
+		found, err := client.SearchActiveRepos(ctx, cfg.Org, login, since, until)
+		if err != nil {
+			return nil, fmt.Errorf("搜索 %s 在组织 %s 下的活动失败: %w", login, cfg.Org, err)
+		}
+		for _, repo := range found {
+			if seen[repo] || !repoAllowed(repo, cfg.RepoInclude, cfg.RepoExclude) {
+				continue
+			}
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+// mergeRepos 合并显式配置的仓库与发现的仓库，按出现顺序去重。
+func mergeRepos(configured, discovered []string) []string {
+	merged := make([]string, 0, len(configured)+len(discovered))
+	seen := make(map[string]bool)
+	for _, repo := range append(configured[:len(configured):len(configured)], discovered...) {
+		if !seen[repo] {
+			seen[repo] = true
+			merged = append(merged, repo)
+		}
+	}
+	return merged
+}
+
+// repoAllowed 判断仓库是否通过 include / exclude 过滤。
+// include 为空时默认包含全部仓库；exclude 优先于 include。
+func repoAllowed(repo string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if matchRepo(pattern, repo) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if matchRepo(pattern, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRepo 使用 glob 模式匹配仓库。
+// 模式包含 "/" 时匹配 "owner/repo" 全名，否则只匹配仓库名，如 "*-service" 或 "acme/web-*"。
+func matchRepo(pattern, repo string) bool {
+	name := repo
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(repo)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
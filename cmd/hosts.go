@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/miclle/gh-report/github"
+	"github.com/miclle/gh-report/report"
+)
+
+// GitHubHost 表示一个额外的 GitHub 主机（如 GitHub Enterprise Server）及其 Token。
+type GitHubHost struct {
+	Host  string `yaml:"host"`  // 主机名，如 ghe.example.com（也可带 https:// 前缀）
+	Token string `yaml:"token"` // 该主机的 Token（可选，默认: $GH_ENTERPRISE_TOKEN，github.com 为 $GITHUB_TOKEN）
+}
+
+// newGitHubClients 创建默认主机的客户端，以及 github_hosts 中每个主机的客户端（以主机名为键）。
+// 仅当存在使用默认主机的仓库或启用组织模式时，才要求默认主机的 Token。
+func newGitHubClients(cfg *Config, opts []github.Option) (*github.Client, map[string]*github.Client, error) {
+	defaultOpts := append(opts[:len(opts):len(opts)], github.WithBaseURL(cfg.GitHubBaseURL))
+	client, err := github.NewClient(hostToken(cfg.Token, cfg.GitHubBaseURL), defaultOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clients := make(map[string]*github.Client, len(cfg.GitHubHosts))
+	for _, h := range cfg.GitHubHosts {
+		token := hostToken(h.Token, h.Host)
+		if token == "" {
+			return nil, nil, fmt.Errorf("未提供主机 %s 的 Token（在 github_hosts 中配置 token 或设置 GH_ENTERPRISE_TOKEN 环境变量）", h.Host)
+		}
+		hostOpts := append(opts[:len(opts):len(opts)], github.WithBaseURL(h.Host))
+		c, err := github.NewClient(token, hostOpts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		clients[c.Host()] = c
+	}
+
+	if cfg.Org != "" && hostToken(cfg.Token, cfg.GitHubBaseURL) == "" {
+		return nil, nil, fmt.Errorf("未提供 GitHub Token（使用 --token 参数、配置文件或 GITHUB_TOKEN 环境变量）")
+	}
+	for _, r := range cfg.Repos {
+		host, _, _, err := report.ParseRepo(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if host != "" && host != client.Host() {
+			if _, ok := clients[host]; !ok {
+				return nil, nil, fmt.Errorf("仓库 %s 所在主机 %s 未在 github_hosts 中配置", r, host)
+			}
+			continue
+		}
+		if hostToken(cfg.Token, cfg.GitHubBaseURL) == "" {
+			return nil, nil, fmt.Errorf("未提供 GitHub Token（使用 --token 参数、配置文件或 GITHUB_TOKEN 环境变量）")
+		}
+	}
+	for _, p := range cfg.Projects {
+		host, _, _, err := report.ParseProject(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		if host != "" && host != client.Host() {
+			if _, ok := clients[host]; !ok {
+				return nil, nil, fmt.Errorf("项目 %s 所在主机 %s 未在 github_hosts 中配置", p, host)
+			}
+		}
+	}
+
+	return client, clients, nil
+}
+
+// hostToken 解析主机的 Token: 配置 > 环境变量。
+// GitHub.com 使用 GITHUB_TOKEN；GitHub Enterprise Server 优先使用 GH_ENTERPRISE_TOKEN，其次 GITHUB_TOKEN。
+func hostToken(token, baseURL string) string {
+	if token != "" {
+		return token
+	}
+	if !isGitHubDotCom(baseURL) {
+		if t := os.Getenv("GH_ENTERPRISE_TOKEN"); t != "" {
+			return t
+		}
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// isGitHubDotCom 判断主机地址是否为空或指向 GitHub.com。
+func isGitHubDotCom(baseURL string) bool {
+	host, err := github.HostOf(baseURL)
+	return err != nil || host == github.DefaultHost
+}
@@ -37,6 +37,8 @@ func runInteractive() error {
 		reportType = ReportMonthly
 	case "yearly":
 		reportType = ReportYearly
+	case "iteration":
+		reportType = ReportIteration
 	default:
 		reportType = ReportDaily
 	}
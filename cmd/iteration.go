@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miclle/gh-report/github"
+	"github.com/miclle/gh-report/report"
+)
+
+var iterationCmd = &cobra.Command{
+	Use:   "iteration",
+	Short: "生成迭代报告（时间范围为 Projects v2 的当前迭代或指定迭代）",
+	Long: `生成迭代报告。
+
+时间范围为 Projects v2 迭代字段中的一个迭代（开始日期 + 持续天数），而不是固定天数：
+默认为当前迭代，也可以通过 --iteration 按标题指定。工作条目为迭代期间完成的工作项，
+计划条目为下一迭代的工作项，以及上一迭代和本迭代中仍未完成的顺延工作项。`,
+	Example: `  # 当前迭代的报告
+  gh-report iteration -c config.yaml -f summary -u mylogin
+
+  # 补生成指定迭代的报告
+  gh-report iteration -c config.yaml -f markdown --iteration "Sprint 42"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReportWithType(cmd, ReportIteration)
+	},
+}
+
+func init() {
+	iterationCmd.Flags().String("iteration", "", "迭代标题（如 \"Sprint 42\"，默认: 当前迭代）")
+	rootCmd.AddCommand(iterationCmd)
+}
+
+// resolveIterationWindow 根据 Projects v2 迭代计算迭代报告的时间范围。
+//
+// 在仓库所有者（组织模式下还包括 cfg.Org）和 cfg.Projects 的项目中查找 cfg.Iteration 指定的迭代，
+// 未指定时查找 now 所在的当前迭代。迭代尚未结束时以 now 为终点；尚未开始的迭代报错。
+func resolveIterationWindow(ctx context.Context, client *github.Client, opts report.Options, cfg *Config, now time.Time) (reportWindow, error) {
+	if cfg.Week != "" || cfg.Month != "" || cfg.Since != "" || cfg.Until != "" {
+		return reportWindow{}, fmt.Errorf("迭代报告的时间范围由迭代决定，不能指定 --week、--month 或 --since/--until")
+	}
+
+	var owners []string
+	if cfg.Org != "" {
+		owners = append(owners, cfg.Org)
+	}
+	iter, err := report.FindIteration(ctx, client, opts, owners, cfg.Iteration, now)
+	if err != nil {
+		return reportWindow{}, fmt.Errorf("查找迭代失败: %w", err)
+	}
+
+	var w reportWindow
+	w.Since, w.Until, err = report.IterationWindow(iter, now.Location())
+	if err != nil {
+		return reportWindow{}, err
+	}
+	if now.Before(w.Since) {
+		return reportWindow{}, fmt.Errorf("迭代 %s 尚未开始（开始日期 %s）", iter.Title, iter.StartDate)
+	}
+	if now.Before(w.Until) {
+		w.Until = now
+	}
+
+	w.FetchSince = startOfDay(w.Until.AddDate(0, 0, -cfg.Days))
+	if w.Since.Before(w.FetchSince) {
+		w.FetchSince = w.Since
+	}
+	return w, nil
+}
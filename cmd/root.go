@@ -3,10 +3,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
+	_ "time/tzdata" // 内嵌时区数据库，保证 --tz 在缺少系统时区数据的环境中可用
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -30,12 +34,14 @@ const (
 	ReportMonthly ReportType = "monthly"
 	// ReportYearly 年报，默认拉取最近 730 天数据。
 	ReportYearly ReportType = "yearly"
+	// ReportIteration 迭代报告，时间范围为 Projects v2 的一个迭代。
+	ReportIteration ReportType = "iteration"
 )
 
 // defaultDays 返回指定报告类型的默认数据拉取天数。
 func defaultDays(rt ReportType) int {
 	switch rt {
-	case ReportWeekly:
+	case ReportWeekly, ReportIteration:
 		return 14
 	case ReportMonthly:
 		return 60
@@ -49,19 +55,58 @@ func defaultDays(rt ReportType) int {
 // Config 表示 YAML 配置文件的结构。
 type Config struct {
 	Token string   `yaml:"token"` // GitHub Token（可选，也可通过 GITHUB_TOKEN 环境变量设置）
-	Repos []string `yaml:"repos"` // 仓库列表，格式为 "owner/repo"
+	Repos []string `yaml:"repos"` // 仓库列表，格式为 "owner/repo" 或 "host/owner/repo"
 	Days  int      `yaml:"days"`  // 查看最近几天的活动
-	User  string   `yaml:"user"`  // 按用户过滤（可选）
+	User  string   `yaml:"user"`  // 按用户过滤（可选），"@org/team" 表示按团队成员过滤
+	Users []string `yaml:"users"` // 团队模式：成员列表，每个成员单独一节
+	Team  string   `yaml:"team"`  // 团队模式：GitHub 团队（org/team-slug），成员通过 Teams API 解析
+
+	Since string `yaml:"since"` // 报告起始日期（格式: 2006-01-02）
+	Until string `yaml:"until"` // 报告截止日期（格式: 2006-01-02，包含当天）
+	Week  string `yaml:"week"`  // 报告 ISO 周（格式: 2006-W01），与 since/until、month 互斥
+	Month string `yaml:"month"` // 报告月份（格式: 2006-01），与 since/until、week 互斥
+
+	Iteration string `yaml:"iteration"` // 迭代报告：迭代标题（如 "Sprint 42"），默认为当前迭代
+
+	Timezone string `yaml:"timezone"` // 报告时区（IANA 名称，如 Asia/Shanghai），默认使用本机时区
+
+	CacheDir string `yaml:"cache_dir"` // HTTP 缓存目录（默认: 用户缓存目录下的 gh-report/http）
+	NoCache  bool   `yaml:"no_cache"`  // 禁用 HTTP 缓存
+
+	Concurrency int  `yaml:"concurrency"`  // GitHub API 最大并发请求数（默认 8）
+	RESTReviews bool `yaml:"rest_reviews"` // 使用 REST 接口逐个获取 PR Review（默认通过 GraphQL 批量获取）
+
+	CommitBranches []string `yaml:"commit_branches"` // 除默认分支外获取提交的分支（glob），默认 release/*、hotfix/*
 
-	Format string `yaml:"format"` // 输出格式：csv（默认）或 summary
+	Org         string   `yaml:"org"`          // 组织模式：通过 Search API 发现用户在该组织下有活动的仓库
+	RepoInclude []string `yaml:"repo_include"` // 组织模式：仓库白名单 glob（如 "*-service"、"acme/web-*"）
+	RepoExclude []string `yaml:"repo_exclude"` // 组织模式：仓库黑名单 glob，优先于白名单
+
+	// 额外关联的 Projects v2 项目，格式为 "owner/number" 或 "host/owner/number"，owner 可以是组织或用户
+	Projects []string `yaml:"projects"`
+
+	ProjectInclude        []string `yaml:"project_include"`         // 只使用仓库所有者下的这些项目（编号或标题），默认全部
+	ProjectIterationField string   `yaml:"project_iteration_field"` // 迭代字段名，默认使用所有迭代字段
+	ProjectStatusField    string   `yaml:"project_status_field"`    // 状态字段名（单选字段），默认 "Status"
+
+	GitHubBaseURL string       `yaml:"github_base_url"` // 默认 GitHub 主机地址（GitHub Enterprise Server），默认 GitHub.com
+	GitHubHosts   []GitHubHost `yaml:"github_hosts"`    // 其他 GitHub 主机及其 Token，配合 "host/owner/repo" 格式的仓库使用
+
+	Format string `yaml:"format"` // 输出格式：csv（默认）、summary、json、markdown 或 html
 	AI     bool   `yaml:"ai"`     // 是否调用 AI API 直接生成日报
 	Model  string `yaml:"model"`  // 模型名称
 
 	// 新字段
-	AIProvider string `yaml:"ai_provider"` // AI 服务提供商: anthropic（默认）或 openai
+	AIProvider string `yaml:"ai_provider"` // AI 服务提供商: anthropic（默认）、openai、azure-openai、gemini 或 ollama
 	AIKey      string `yaml:"ai_key"`      // AI API Key
 	AIBaseURL  string `yaml:"ai_base_url"` // AI API Base URL
 
+	AIDeployment string `yaml:"ai_deployment"`  // Azure OpenAI 部署名（仅 azure-openai）
+	AIAPIVersion string `yaml:"ai_api_version"` // Azure OpenAI API 版本（仅 azure-openai），默认 2024-10-21
+
+	AIMaxTokens    int `yaml:"ai_max_tokens"`    // 单次生成的最大输出 token 数，默认 4096
+	AIPromptTokens int `yaml:"ai_prompt_tokens"` // 单个 Prompt 的 token 上限（估算），超出时分段总结，默认 60000
+
 	// 已废弃字段（向后兼容）
 	AnthropicKey     string `yaml:"anthropic_key"`      // 已废弃，请使用 ai_key
 	AnthropicBaseURL string `yaml:"anthropic_base_url"` // 已废弃，请使用 ai_base_url
@@ -90,7 +135,7 @@ var rootCmd = &cobra.Command{
 通过 GitHub API 获取指定仓库的 Issue、Pull Request、评论、Review 以及
 Projects v2 迭代信息，生成结构化的工作报告。支持日报、周报、月报、年报，
 提供 CSV 原始数据输出和 Summary 模式，并可通过 AI API（支持 Anthropic
-Claude 和 OpenAI）直接生成报告。
+Claude、OpenAI、Azure OpenAI、Google Gemini 和本地部署的 Ollama）直接生成报告。
 
 不指定子命令时默认生成日报。`,
 	Example: `  # 使用配置文件生成日报（默认）
@@ -108,11 +153,64 @@ Claude 和 OpenAI）直接生成报告。
   # 指定仓库，自定义天数
   gh-report weekly -r owner/repo1,owner/repo2 -d 21 -u mylogin
 
+  # 补生成上周的周报（ISO 周）
+  gh-report weekly -c config.yaml -f summary --week 2026-W40
+
+  # 生成当前迭代的迭代报告（时间范围为 Projects v2 的当前迭代）
+  gh-report iteration -c config.yaml -f summary --ai
+
+  # 补生成指定迭代的迭代报告
+  gh-report iteration -c config.yaml -f summary --iteration "Sprint 42"
+
+  # 补生成指定月份的月报
+  gh-report monthly -c config.yaml -f summary --month 2026-09
+
+  # 指定任意日期范围（包含 until 当天）
+  gh-report weekly -c config.yaml --since 2026-09-01 --until 2026-09-10
+
+  # 按团队成员过滤，生成一份合并的周报（成员随组织变动自动更新）
+  gh-report weekly -c config.yaml -f summary -u @myorg/backend
+
+  # 组织模式：自动发现用户在组织下有活动的仓库，无需逐个列出
+  gh-report weekly --org myorg -u mylogin -f summary
+
+  # 团队周报：按成员分节，附团队概览
+  gh-report weekly -c config.yaml -f summary --team myorg/backend
+
+  # 按指定时区划分日期边界
+  gh-report weekly -c config.yaml --tz Europe/Berlin
+
   # 生成摘要（可粘贴给 AI）
   gh-report -c config.yaml -f summary
 
+  # 输出 Markdown（可直接粘贴到 Wiki 或聊天工具，无需 AI）
+  gh-report weekly -c config.yaml -f markdown -u mylogin
+
+  # 输出离线 HTML 报告（可作为邮件附件发送）
+  gh-report weekly -c config.yaml -f html > report.html
+
+  # 输出 JSON（供脚本或看板使用）
+  gh-report weekly -c config.yaml -f json > report.json
+
+  # 使用 GitHub Enterprise Server
+  gh-report weekly -c config.yaml --github-host ghe.example.com
+
+  # 跳过 HTTP 缓存，强制重新获取全部数据
+  gh-report weekly -c config.yaml --no-cache
+
+  # 查看或清空 HTTP 缓存
+  gh-report cache stats
+  gh-report cache clear
+
   # 使用 OpenAI 生成日报
-  gh-report -c config.yaml -f summary --ai --ai-provider openai`,
+  gh-report -c config.yaml -f summary --ai --ai-provider openai
+
+  # 使用 Azure OpenAI 生成日报（按部署名访问模型）
+  gh-report -c config.yaml -f summary --ai --ai-provider azure-openai \
+    --ai-base-url https://myres.openai.azure.com --ai-deployment gpt-4o
+
+  # 使用本地 Ollama 模型生成日报（数据不离开本机，无需 API Key）
+  gh-report -c config.yaml -f summary --ai --ai-provider ollama --model qwen2.5`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -125,14 +223,29 @@ func init() {
 	f.StringP("config", "c", "", "YAML 配置文件路径")
 	f.StringP("repos", "r", "", "仓库列表，逗号分隔（owner/repo 格式）")
 	f.IntP("days", "d", 0, "查看最近几天的活动")
-	f.StringP("user", "u", "", "按用户过滤")
+	f.StringP("user", "u", "", "按用户过滤（@org/team 表示按团队成员过滤）")
+	f.String("team", "", "团队模式：GitHub 团队（org/team-slug），按成员分节生成报告")
+	f.String("org", "", "组织模式：通过 Search API 发现用户在该组织下有活动的仓库")
+	f.String("since", "", "报告起始日期（格式: 2006-01-02）")
+	f.String("until", "", "报告截止日期（格式: 2006-01-02，包含当天）")
+	f.String("week", "", "报告 ISO 周（格式: 2006-W01）")
+	f.String("month", "", "报告月份（格式: 2006-01）")
+	f.String("tz", "", "报告时区（IANA 名称，如 Asia/Shanghai，默认: 本机时区）")
 	f.String("token", "", "GitHub Token（默认: $GITHUB_TOKEN）")
-	f.StringP("format", "f", "", "输出格式: csv（默认）或 summary")
+	f.String("github-host", "", "GitHub Enterprise Server 地址（如 ghe.example.com，默认: github.com）")
+	f.Bool("no-cache", false, "禁用 HTTP 缓存，所有请求直接访问 GitHub API（缓存只作用于 REST 请求，GraphQL 获取的 PR、Review 和 Projects 始终不缓存）")
+	f.Int("concurrency", 0, fmt.Sprintf("GitHub API 最大并发请求数（默认: %d）", github.DefaultConcurrency))
+	f.Bool("rest-reviews", false, "使用 REST 接口逐个获取 PR Review（默认通过 GraphQL 批量获取）")
+	f.StringP("format", "f", "", "输出格式: csv（默认）、summary、json、markdown 或 html")
 	f.Bool("ai", false, "调用 AI API 生成报告")
-	f.String("ai-provider", "", "AI 服务提供商: anthropic（默认）或 openai")
+	f.String("ai-provider", "", "AI 服务提供商: anthropic（默认）、openai、azure-openai、gemini 或 ollama")
 	f.String("ai-key", "", "AI API Key（默认: 按 provider 查环境变量）")
 	f.String("ai-base-url", "", "AI API Base URL")
-	f.String("model", "", "AI 模型名")
+	f.String("model", "", "AI 模型名（azure-openai 忽略，使用 --ai-deployment 指定的部署）")
+	f.String("ai-deployment", "", "Azure OpenAI 部署名（仅 azure-openai）")
+	f.String("ai-api-version", "", "Azure OpenAI API 版本（仅 azure-openai，默认 2024-10-21）")
+	f.Int("ai-max-tokens", 0, "AI 单次生成的最大输出 token 数（默认 4096）")
+	f.Int("ai-prompt-tokens", 0, "AI Prompt 的 token 上限，超出时分段总结后合并（默认 60000）")
 
 	// 已废弃 flags（向后兼容）
 	f.String("anthropic-key", "", "Anthropic API Key（已废弃，请使用 --ai-key）")
@@ -181,9 +294,45 @@ func runReportWithType(cmd *cobra.Command, reportType ReportType) error {
 	if cmd.Flags().Changed("user") {
 		cfg.User, _ = cmd.Flags().GetString("user")
 	}
+	if cmd.Flags().Changed("team") {
+		cfg.Team, _ = cmd.Flags().GetString("team")
+	}
+	if cmd.Flags().Changed("org") {
+		cfg.Org, _ = cmd.Flags().GetString("org")
+	}
+	if cmd.Flags().Changed("since") {
+		cfg.Since, _ = cmd.Flags().GetString("since")
+	}
+	if cmd.Flags().Changed("until") {
+		cfg.Until, _ = cmd.Flags().GetString("until")
+	}
+	if cmd.Flags().Changed("week") {
+		cfg.Week, _ = cmd.Flags().GetString("week")
+	}
+	if cmd.Flags().Changed("month") {
+		cfg.Month, _ = cmd.Flags().GetString("month")
+	}
+	if cmd.Flags().Changed("iteration") {
+		cfg.Iteration, _ = cmd.Flags().GetString("iteration")
+	}
+	if cmd.Flags().Changed("tz") {
+		cfg.Timezone, _ = cmd.Flags().GetString("tz")
+	}
 	if cmd.Flags().Changed("token") {
 		cfg.Token, _ = cmd.Flags().GetString("token")
 	}
+	if cmd.Flags().Changed("github-host") {
+		cfg.GitHubBaseURL, _ = cmd.Flags().GetString("github-host")
+	}
+	if cmd.Flags().Changed("no-cache") {
+		cfg.NoCache, _ = cmd.Flags().GetBool("no-cache")
+	}
+	if cmd.Flags().Changed("concurrency") {
+		cfg.Concurrency, _ = cmd.Flags().GetInt("concurrency")
+	}
+	if cmd.Flags().Changed("rest-reviews") {
+		cfg.RESTReviews, _ = cmd.Flags().GetBool("rest-reviews")
+	}
 	if cmd.Flags().Changed("format") {
 		cfg.Format, _ = cmd.Flags().GetString("format")
 	}
@@ -209,6 +358,18 @@ func runReportWithType(cmd *cobra.Command, reportType ReportType) error {
 	if cmd.Flags().Changed("model") {
 		cfg.Model, _ = cmd.Flags().GetString("model")
 	}
+	if cmd.Flags().Changed("ai-deployment") {
+		cfg.AIDeployment, _ = cmd.Flags().GetString("ai-deployment")
+	}
+	if cmd.Flags().Changed("ai-api-version") {
+		cfg.AIAPIVersion, _ = cmd.Flags().GetString("ai-api-version")
+	}
+	if cmd.Flags().Changed("ai-max-tokens") {
+		cfg.AIMaxTokens, _ = cmd.Flags().GetInt("ai-max-tokens")
+	}
+	if cmd.Flags().Changed("ai-prompt-tokens") {
+		cfg.AIPromptTokens, _ = cmd.Flags().GetInt("ai-prompt-tokens")
+	}
 
 	return runReportWithConfig(reportType, &cfg)
 }
@@ -220,30 +381,126 @@ func runReportWithConfig(reportType ReportType, cfg *Config) error {
 		cfg.Days = defaultDays(reportType)
 	}
 
-	if len(cfg.Repos) == 0 {
-		return fmt.Errorf("未指定仓库（使用 -r 参数或配置文件指定）")
+	if len(cfg.Repos) == 0 && cfg.Org == "" {
+		return fmt.Errorf("未指定仓库（使用 -r 参数、--org 参数或配置文件指定）")
 	}
 
-	// 解析 Token: flag/config > 环境变量
-	ghToken := cfg.Token
-	if ghToken == "" {
-		ghToken = os.Getenv("GITHUB_TOKEN")
+	// 所有按天归类、时间基准和迭代分类均在报告时区中进行
+	loc := time.Local
+	if cfg.Timezone != "" {
+		l, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return fmt.Errorf("无效的时区 %q: %w", cfg.Timezone, err)
+		}
+		loc = l
 	}
-	if ghToken == "" {
-		return fmt.Errorf("未提供 GitHub Token（使用 --token 参数、配置文件或 GITHUB_TOKEN 环境变量）")
+
+	now := time.Now().In(loc)
+
+	// 进度条先创建，以便接收数据收集前（如解析团队成员）的配额信息
+	progress := ui.NewProgress(cfg.Repos)
+
+	clientOpts := []github.Option{
+		github.WithConcurrency(cfg.Concurrency),
+		github.WithRateLimitObserver(progress.SetRateLimit),
+	}
+	if !cfg.NoCache {
+		dir, err := resolveCacheDir(cfg)
+		if err != nil {
+			return err
+		}
+		clientOpts = append(clientOpts, github.WithCache(dir))
+	}
+	client, hostClients, err := newGitHubClients(cfg, clientOpts)
+	if err != nil {
+		return err
+	}
+	// Ctrl-C 取消 context，中断进行中的请求（如 AI 生成）
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	projectOpts := github.ProjectOptions{
+		Include:        cfg.ProjectInclude,
+		IterationField: cfg.ProjectIterationField,
+		StatusField:    cfg.ProjectStatusField,
 	}
 
-	client := github.NewClient(ghToken)
-	ctx := context.Background()
+	// 迭代报告的时间范围取决于 Projects v2 中的迭代，需要先查询项目
+	var window reportWindow
+	if reportType == ReportIteration {
+		window, err = resolveIterationWindow(ctx, client, report.Options{
+			Repos:          cfg.Repos,
+			Projects:       cfg.Projects,
+			ProjectOptions: projectOpts,
+			Clients:        hostClients,
+		}, cfg, now)
+	} else {
+		window, err = resolveWindow(cfg, reportType, now)
+	}
+	if err != nil {
+		return err
+	}
+	since, until := window.Since, window.Until
+
+	// 团队模式：解析成员列表，数据只收集一次，再按成员分别提取
+	if cfg.User != "" && (len(cfg.Users) > 0 || cfg.Team != "") {
+		return fmt.Errorf("user 与 users/team 不能同时指定")
+	}
+	members, err := resolveTeamMembers(ctx, client, cfg)
+	if err != nil {
+		return err
+	}
+	teamMode := len(members) > 0
+	userLabel := cfg.User
+	if teamMode {
+		userLabel = cfg.Team
+		if userLabel == "" {
+			userLabel = strings.Join(members, ",")
+		}
+	}
 
 	opts := report.Options{
 		Repos: cfg.Repos,
 		Days:  cfg.Days,
+		Since: window.FetchSince,
+		Until: window.Until,
 		User:  cfg.User,
+		Users: members,
+
+		RESTReviews:    cfg.RESTReviews,
+		CommitBranches: cfg.CommitBranches,
+		Projects:       cfg.Projects,
+		ProjectOptions: projectOpts,
+		Clients:        hostClients,
+	}
+
+	// user 为团队引用（@org/team）时，collector 按团队成员集合过滤，报告不按成员分节
+	if report.IsTeamRef(cfg.User) {
+		opts.User = ""
+		opts.Users, err = fetchTeamMembers(ctx, client, strings.TrimPrefix(cfg.User, "@"))
+		if err != nil {
+			return err
+		}
+	}
+
+	// 组织模式：通过 Search API 发现有活动的仓库，与显式配置的仓库合并
+	if cfg.Org != "" {
+		logins := opts.Users
+		if opts.User != "" {
+			logins = []string{opts.User}
+		}
+		found, err := discoverOrgRepos(ctx, client, cfg, logins, since, until)
+		if err != nil {
+			return err
+		}
+		opts.Repos = mergeRepos(cfg.Repos, found)
+		if len(opts.Repos) == 0 {
+			return fmt.Errorf("在组织 %s 下未发现 %s 的活动仓库", cfg.Org, userLabel)
+		}
+		progress.SetRepos(opts.Repos)
 	}
 
 	// 使用新的 UI 进度组件获取 GitHub 数据
-	progress := ui.NewProgress(opts.Repos)
 	wrapper := progress.Start()
 	reports, err := report.Collect(ctx, client, opts, wrapper)
 	if err != nil {
@@ -254,10 +511,31 @@ func runReportWithConfig(reportType ReportType, cfg *Config) error {
 	progress.Complete()
 	progress.Stop()
 
-	now := time.Now()
-	since := now.AddDate(0, 0, -cfg.Days)
-
 	switch cfg.Format {
+	case "json":
+		if teamMode {
+			err = report.PrintTeamJSON(os.Stdout, reports, since, until, userLabel, members, report.ReportType(reportType))
+		} else {
+			err = report.PrintJSON(os.Stdout, reports, since, until, userLabel, report.ReportType(reportType))
+		}
+		if err != nil {
+			return fmt.Errorf("输出 JSON 失败: %w", err)
+		}
+	case "markdown":
+		if teamMode {
+			report.PrintTeamMarkdown(os.Stdout, reports, since, until, members, report.ReportType(reportType))
+		} else {
+			report.PrintMarkdown(os.Stdout, reports, since, until, cfg.User, report.ReportType(reportType))
+		}
+	case "html":
+		if teamMode {
+			err = report.PrintTeamHTML(os.Stdout, reports, since, until, userLabel, members, report.ReportType(reportType))
+		} else {
+			err = report.PrintHTML(os.Stdout, reports, since, until, userLabel, report.ReportType(reportType))
+		}
+		if err != nil {
+			return fmt.Errorf("输出 HTML 失败: %w", err)
+		}
 	case "summary":
 		if cfg.AI {
 			// 解析 AI Provider
@@ -267,6 +545,7 @@ func runReportWithConfig(reportType ReportType, cfg *Config) error {
 			}
 
 			// 解析 API Key: --ai-key > ai_key > --anthropic-key(兼容) > anthropic_key(兼容) > 按 provider 查环境变量 > $AI_API_KEY
+			// 本地部署的 provider（ollama）不需要 API Key
 			apiKey := cfg.AIKey
 			if apiKey == "" {
 				apiKey = cfg.AnthropicKey // 兼容旧配置
@@ -277,12 +556,16 @@ func runReportWithConfig(reportType ReportType, cfg *Config) error {
 					apiKey = os.Getenv("ANTHROPIC_API_KEY")
 				case ai.ProviderOpenAI:
 					apiKey = os.Getenv("OPENAI_API_KEY")
+				case ai.ProviderGemini:
+					apiKey = os.Getenv("GEMINI_API_KEY")
+				case ai.ProviderAzureOpenAI:
+					apiKey = os.Getenv("AZURE_OPENAI_API_KEY")
 				}
 			}
 			if apiKey == "" {
 				apiKey = os.Getenv("AI_API_KEY")
 			}
-			if apiKey == "" {
+			if apiKey == "" && ai.RequiresAPIKey(provider) {
 				return fmt.Errorf("未提供 AI API Key（使用 --ai-key 参数、配置文件或环境变量）")
 			}
 
@@ -297,35 +580,61 @@ func runReportWithConfig(reportType ReportType, cfg *Config) error {
 					baseURL = os.Getenv("ANTHROPIC_BASE_URL")
 				case ai.ProviderOpenAI:
 					baseURL = os.Getenv("OPENAI_BASE_URL")
+				case ai.ProviderOllama:
+					baseURL = os.Getenv("OLLAMA_HOST")
+				case ai.ProviderAzureOpenAI:
+					baseURL = os.Getenv("AZURE_OPENAI_ENDPOINT")
 				}
 			}
 
-			prompt := report.BuildSummaryPrompt(reports, since, now, cfg.User, report.ReportType(reportType))
+			// Azure OpenAI 的部署名和 API 版本: 命令行参数 > 配置文件 > 环境变量
+			deployment := cfg.AIDeployment
+			if deployment == "" {
+				deployment = os.Getenv("AZURE_OPENAI_DEPLOYMENT")
+			}
+			apiVersion := cfg.AIAPIVersion
+			if apiVersion == "" {
+				apiVersion = os.Getenv("OPENAI_API_VERSION")
+			}
+
+			// Prompt 过大时分段总结后合并，团队模式按成员分段
+			var plan report.SummaryPlan
+			if teamMode {
+				plan = report.PlanTeamSummary(reports, since, until, members, report.ReportType(reportType), cfg.AIPromptTokens)
+			} else {
+				plan = report.PlanSummary(reports, since, until, cfg.User, report.ReportType(reportType), cfg.AIPromptTokens)
+			}
 			aiClient, err := ai.NewClient(ai.Config{
-				Provider: provider,
-				APIKey:   apiKey,
-				Model:    cfg.Model,
-				BaseURL:  baseURL,
+				Provider:  provider,
+				APIKey:    apiKey,
+				Model:     cfg.Model,
+				BaseURL:   baseURL,
+				MaxTokens: cfg.AIMaxTokens,
+
+				Deployment: deployment,
+				APIVersion: apiVersion,
 			})
 			if err != nil {
 				return err
 			}
 
-			// 使用新的 UI Spinner 调用 AI API
 			reportName := reportTypeLabel(reportType)
-			spinnerText := fmt.Sprintf("正在调用 %s API 生成%s...", provider, reportName)
-			result, err := ui.RunSpinnerWithResult(spinnerText, func() (string, error) {
-				return aiClient.CreateMessage(ctx, prompt)
-			})
+			err = generateAIReport(ctx, aiClient, plan, provider, reportName)
+			if errors.Is(err, context.Canceled) {
+				return fmt.Errorf("已取消生成%s", reportName)
+			}
 			if err != nil {
 				return fmt.Errorf("调用 %s API 失败: %w", provider, err)
 			}
-			fmt.Fprintln(os.Stdout, result)
 		} else {
-			report.PrintSummaryData(os.Stdout, reports, since, now, cfg.User, report.ReportType(reportType))
+			if teamMode {
+				report.PrintTeamSummaryData(os.Stdout, reports, since, until, members, report.ReportType(reportType))
+			} else {
+				report.PrintSummaryData(os.Stdout, reports, since, until, cfg.User, report.ReportType(reportType))
+			}
 		}
 	default:
-		report.Print(os.Stdout, reports, since, now)
+		report.Print(os.Stdout, reports, since, until)
 	}
 
 	return nil
@@ -340,6 +649,8 @@ func reportTypeLabel(rt ReportType) string {
 		return "月报"
 	case ReportYearly:
 		return "年报"
+	case ReportIteration:
+		return "迭代报告"
 	default:
 		return "日报"
 	}
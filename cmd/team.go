@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/miclle/gh-report/github"
+	"github.com/miclle/gh-report/report"
+)
+
+// resolveTeamMembers 合并配置中的 users 列表与 team（org/team-slug）的成员，按出现顺序去重。
+// users 中以 @ 开头的条目（如 "@org/team"）会展开为该团队的成员。
+// 均未指定时返回 nil，表示非团队模式。
+func resolveTeamMembers(ctx context.Context, client *github.Client, cfg *Config) ([]string, error) {
+	var members []string
+	seen := make(map[string]bool)
+	add := func(login string) {
+		login = strings.TrimSpace(login)
+		if login == "" || seen[login] {
+			return
+		}
+		seen[login] = true
+		members = append(members, login)
+	}
+
+	for _, u := range cfg.Users {
+		if !report.IsTeamRef(u) {
+			add(u)
+			continue
+		}
+		logins, err := fetchTeamMembers(ctx, client, strings.TrimPrefix(u, "@"))
+		if err != nil {
+			return nil, err
+		}
+		for _, login := range logins {
+			add(login)
+		}
+	}
+
+	if cfg.Team != "" {
+		logins, err := fetchTeamMembers(ctx, client, strings.TrimPrefix(cfg.Team, "@"))
+		if err != nil {
+			return nil, err
+		}
+		for _, login := range logins {
+			add(login)
+		}
+	}
+
+	return members, nil
+}
+
+// fetchTeamMembers 通过 Teams API 获取团队（org/team-slug）的成员列表。
+func fetchTeamMembers(ctx context.Context, client *github.Client, team string) ([]string, error) {
+	org, slug, ok := strings.Cut(team, "/")
+	if !ok || org == "" || slug == "" {
+		return nil, fmt.Errorf("无效的团队 %q（格式: org/team-slug）", team)
+	}
+	logins, err := client.ListTeamMembers(ctx, org, slug)
+	if err != nil {
+		return nil, fmt.Errorf("获取团队 %s 成员失败: %w", team, err)
+	}
+	if len(logins) == 0 {
+		return nil, fmt.Errorf("团队 %s 没有成员", team)
+	}
+	return logins, nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miclle/gh-report/report"
+)
+
+// reportWindow 表示一次报告运行的时间范围。
+type reportWindow struct {
+	FetchSince time.Time // 数据获取起点，传给 GitHub API
+	Since      time.Time // 报告时间范围起点，作为工作条目的过滤基准
+	Until      time.Time // 报告时间范围终点，之后发生的活动被排除
+}
+
+// resolveWindow 根据配置计算报告时间范围。
+//
+// 支持三种互斥的指定方式：--week（ISO 周，如 2026-W40）、--month（如 2026-09）、
+// --since/--until（日期，如 2026-09-01）。均未指定时以当前时间为终点，
+// 起点由报告类型决定（当天、本周一、本月一号或今年一月一号）。
+// 数据获取起点取报告起点与 until 前推 days 天（取整到零点）中较早的一个，以便为计划条目提供上下文。
+func resolveWindow(cfg *Config, rt ReportType, now time.Time) (reportWindow, error) {
+	loc := now.Location()
+
+	specified := 0
+	for _, v := range []string{cfg.Week, cfg.Month, cfg.Since + cfg.Until} {
+		if v != "" {
+			specified++
+		}
+	}
+	if specified > 1 {
+		return reportWindow{}, fmt.Errorf("--week、--month 与 --since/--until 只能指定一种")
+	}
+
+	var w reportWindow
+	switch {
+	case cfg.Week != "":
+		monday, err := parseISOWeek(cfg.Week, loc)
+		if err != nil {
+			return reportWindow{}, err
+		}
+		w.Since = monday
+		w.Until = endOfDay(monday.AddDate(0, 0, 6))
+	case cfg.Month != "":
+		first, err := time.ParseInLocation("2006-01", cfg.Month, loc)
+		if err != nil {
+			return reportWindow{}, fmt.Errorf("无效的月份 %q（格式: 2006-01）", cfg.Month)
+		}
+		w.Since = first
+		w.Until = endOfDay(first.AddDate(0, 1, -1))
+	default:
+		w.Until = now
+		if cfg.Until != "" {
+			d, err := time.ParseInLocation("2006-01-02", cfg.Until, loc)
+			if err != nil {
+				return reportWindow{}, fmt.Errorf("无效的 until 日期 %q（格式: 2006-01-02）", cfg.Until)
+			}
+			w.Until = endOfDay(d)
+		}
+		w.Since = report.WorkTimeCutoff(report.ReportType(rt), w.Until)
+		if cfg.Since != "" {
+			d, err := time.ParseInLocation("2006-01-02", cfg.Since, loc)
+			if err != nil {
+				return reportWindow{}, fmt.Errorf("无效的 since 日期 %q（格式: 2006-01-02）", cfg.Since)
+			}
+			w.Since = d
+		}
+	}
+
+	if !w.Since.Before(w.Until) {
+		return reportWindow{}, fmt.Errorf("时间范围无效: %s ~ %s", w.Since.Format("2006-01-02"), w.Until.Format("2006-01-02"))
+	}
+
+	// 数据获取起点取整到当天零点，使 API 请求 URL 在同一天内保持稳定，便于 HTTP 缓存命中
+	w.FetchSince = startOfDay(w.Until.AddDate(0, 0, -cfg.Days))
+	if w.Since.Before(w.FetchSince) {
+		w.FetchSince = w.Since
+	}
+	return w, nil
+}
+
+// parseISOWeek 解析 ISO 8601 周（如 "2026-W40"），返回该周周一零点。
+func parseISOWeek(s string, loc *time.Location) (time.Time, error) {
+	invalid := fmt.Errorf("无效的周 %q（格式: 2026-W40）", s)
+
+	yearStr, weekStr, ok := strings.Cut(strings.ToUpper(s), "-W")
+	if !ok {
+		return time.Time{}, invalid
+	}
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+	week, err := strconv.Atoi(weekStr)
+	if err != nil || week < 1 || week > 53 {
+		return time.Time{}, invalid
+	}
+
+	// 1 月 4 日总是落在 ISO 第 1 周
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, loc)
+	weekday := int(jan4.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	monday := jan4.AddDate(0, 0, 1-weekday+(week-1)*7)
+
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return time.Time{}, invalid
+	}
+	return monday, nil
+}
+
+// startOfDay 返回指定日期当天的零点。
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// endOfDay 返回指定日期当天的最后一刻。
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond)
+}
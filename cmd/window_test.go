@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// day 返回 loc 中指定日期的零点。
+func day(loc *time.Location, year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, loc)
+}
+
+func TestParseISOWeek(t *testing.T) {
+	tests := []struct {
+		week    string
+		want    time.Time
+		wantErr bool
+	}{
+		{week: "2026-W40", want: day(time.UTC, 2026, 9, 28)},
+		{week: "2026-w40", want: day(time.UTC, 2026, 9, 28)},
+		// 第 1 周的周一可能落在上一年
+		{week: "2025-W01", want: day(time.UTC, 2024, 12, 30)},
+		{week: "2026-W01", want: day(time.UTC, 2025, 12, 29)},
+		// 1 月 1 日为周五时属于上一年的最后一周
+		{week: "2021-W01", want: day(time.UTC, 2021, 1, 4)},
+		// 以周四开始的年份（及以周三开始的闰年）有 53 周
+		{week: "2020-W53", want: day(time.UTC, 2020, 12, 28)},
+		{week: "2026-W53", want: day(time.UTC, 2026, 12, 28)},
+		{week: "2025-W53", wantErr: true},
+		{week: "2026-W00", wantErr: true},
+		{week: "2026-W54", wantErr: true},
+		{week: "2026-40", wantErr: true},
+		{week: "2026-Wx", wantErr: true},
+		{week: "abcd-W01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.week, func(t *testing.T) {
+			got, err := parseISOWeek(tt.week, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseISOWeek(%q) = %v, want error", tt.week, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseISOWeek(%q) error = %v", tt.week, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseISOWeek(%q) = %v, want %v", tt.week, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWindow(t *testing.T) {
+	// 2026-10-16 是周五
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lastNano := 24*time.Hour - time.Nanosecond
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		rt        ReportType
+		wantSince time.Time
+		wantUntil time.Time
+		wantFetch time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "default daily",
+			cfg:       Config{Days: 7},
+			rt:        ReportDaily,
+			wantSince: day(time.UTC, 2026, 10, 16),
+			wantUntil: now,
+			wantFetch: day(time.UTC, 2026, 10, 9),
+		},
+		{
+			name:      "default weekly",
+			cfg:       Config{Days: 1},
+			rt:        ReportWeekly,
+			wantSince: day(time.UTC, 2026, 10, 12),
+			wantUntil: now,
+			wantFetch: day(time.UTC, 2026, 10, 12),
+		},
+		{
+			name:      "week across year boundary",
+			cfg:       Config{Week: "2025-W01"},
+			rt:        ReportWeekly,
+			wantSince: day(time.UTC, 2024, 12, 30),
+			wantUntil: day(time.UTC, 2025, 1, 5).Add(lastNano),
+			wantFetch: day(time.UTC, 2024, 12, 30),
+		},
+		{
+			name:      "week 53",
+			cfg:       Config{Week: "2020-W53"},
+			rt:        ReportWeekly,
+			wantSince: day(time.UTC, 2020, 12, 28),
+			wantUntil: day(time.UTC, 2021, 1, 3).Add(lastNano),
+			wantFetch: day(time.UTC, 2020, 12, 28),
+		},
+		{
+			name:    "invalid week 53",
+			cfg:     Config{Week: "2025-W53"},
+			rt:      ReportWeekly,
+			wantErr: true,
+		},
+		{
+			name:      "month",
+			cfg:       Config{Month: "2026-12", Days: 7},
+			rt:        ReportMonthly,
+			wantSince: day(time.UTC, 2026, 12, 1),
+			wantUntil: day(time.UTC, 2026, 12, 31).Add(lastNano),
+			wantFetch: day(time.UTC, 2026, 12, 1),
+		},
+		{
+			name:      "leap february",
+			cfg:       Config{Month: "2024-02"},
+			rt:        ReportMonthly,
+			wantSince: day(time.UTC, 2024, 2, 1),
+			wantUntil: day(time.UTC, 2024, 2, 29).Add(lastNano),
+			wantFetch: day(time.UTC, 2024, 2, 1),
+		},
+		{
+			name:    "invalid month",
+			cfg:     Config{Month: "2026-13"},
+			rt:      ReportMonthly,
+			wantErr: true,
+		},
+		{
+			name:      "since until across year boundary",
+			cfg:       Config{Since: "2025-12-30", Until: "2026-01-02", Days: 7},
+			rt:        ReportWeekly,
+			wantSince: day(time.UTC, 2025, 12, 30),
+			wantUntil: day(time.UTC, 2026, 1, 2).Add(lastNano),
+			wantFetch: day(time.UTC, 2025, 12, 26),
+		},
+		{
+			name:      "until is inclusive",
+			cfg:       Config{Since: "2026-10-01", Until: "2026-10-01"},
+			rt:        ReportDaily,
+			wantSince: day(time.UTC, 2026, 10, 1),
+			wantUntil: day(time.UTC, 2026, 10, 1).Add(lastNano),
+			wantFetch: day(time.UTC, 2026, 10, 1),
+		},
+		{
+			name:      "until only",
+			cfg:       Config{Until: "2026-10-07"},
+			rt:        ReportWeekly,
+			wantSince: day(time.UTC, 2026, 10, 5),
+			wantUntil: day(time.UTC, 2026, 10, 7).Add(lastNano),
+			wantFetch: day(time.UTC, 2026, 10, 5),
+		},
+		{
+			name:    "since after until",
+			cfg:     Config{Since: "2026-10-02", Until: "2026-10-01"},
+			rt:      ReportDaily,
+			wantErr: true,
+		},
+		{
+			name:    "since after now",
+			cfg:     Config{Since: "2026-10-20"},
+			rt:      ReportDaily,
+			wantErr: true,
+		},
+		{
+			name:    "invalid since",
+			cfg:     Config{Since: "2026/10/01"},
+			rt:      ReportDaily,
+			wantErr: true,
+		},
+		{
+			name:    "week and month",
+			cfg:     Config{Week: "2026-W40", Month: "2026-10"},
+			rt:      ReportWeekly,
+			wantErr: true,
+		},
+		{
+			name:    "month and since",
+			cfg:     Config{Month: "2026-10", Since: "2026-10-01"},
+			rt:      ReportMonthly,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveWindow(&tt.cfg, tt.rt, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveWindow() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveWindow() error = %v", err)
+			}
+			if !got.Since.Equal(tt.wantSince) {
+				t.Errorf("Since = %v, want %v", got.Since, tt.wantSince)
+			}
+			if !got.Until.Equal(tt.wantUntil) {
+				t.Errorf("Until = %v, want %v", got.Until, tt.wantUntil)
+			}
+			if !got.FetchSince.Equal(tt.wantFetch) {
+				t.Errorf("FetchSince = %v, want %v", got.FetchSince, tt.wantFetch)
+			}
+		})
+	}
+}
+
+// loadLocation 加载 IANA 时区，系统缺少时区数据时跳过测试。
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestResolveWindowTimezone(t *testing.T) {
+	shanghai := loadLocation(t, "Asia/Shanghai")
+	losAngeles := loadLocation(t, "America/Los_Angeles")
+
+	// 同一时刻在东八区已是 10-16，在太平洋时区仍是 10-15
+	instant := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		loc       *time.Location
+		wantSince time.Time
+	}{
+		{loc: shanghai, wantSince: day(shanghai, 2026, 10, 16)},
+		{loc: losAngeles, wantSince: day(losAngeles, 2026, 10, 15)},
+		{loc: time.UTC, wantSince: day(time.UTC, 2026, 10, 16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.loc.String(), func(t *testing.T) {
+			got, err := resolveWindow(&Config{}, ReportDaily, instant.In(tt.loc))
+			if err != nil {
+				t.Fatalf("resolveWindow() error = %v", err)
+			}
+			if !got.Since.Equal(tt.wantSince) {
+				t.Errorf("Since = %v, want %v", got.Since, tt.wantSince)
+			}
+		})
+	}
+
+	// 显式日期按报告时区解释，而非 UTC
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, shanghai)
+	got, err := resolveWindow(&Config{Since: "2026-10-01", Until: "2026-10-01"}, ReportDaily, now)
+	if err != nil {
+		t.Fatalf("resolveWindow() error = %v", err)
+	}
+	if want := time.Date(2026, 9, 30, 16, 0, 0, 0, time.UTC); !got.Since.Equal(want) {
+		t.Errorf("Since = %v, want %v", got.Since.UTC(), want)
+	}
+	if want := time.Date(2026, 10, 1, 16, 0, 0, 0, time.UTC).Add(-time.Nanosecond); !got.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", got.Until.UTC(), want)
+	}
+}
+
+func TestResolveWindowDST(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	berlin := loadLocation(t, "Europe/Berlin")
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		loc       *time.Location
+		wantSince time.Time
+		wantUntil time.Time
+		wantLen   time.Duration
+	}{
+		{
+			// 2026-03-08 纽约进入夏令时，当天只有 23 小时
+			name:      "spring forward day",
+			cfg:       Config{Since: "2026-03-08", Until: "2026-03-08"},
+			loc:       newYork,
+			wantSince: time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC),
+			wantUntil: time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantLen:   23*time.Hour - time.Nanosecond,
+		},
+		{
+			name:      "spring forward week",
+			cfg:       Config{Week: "2026-W10"},
+			loc:       newYork,
+			wantSince: time.Date(2026, 3, 2, 5, 0, 0, 0, time.UTC),
+			wantUntil: time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantLen:   7*24*time.Hour - time.Hour - time.Nanosecond,
+		},
+		{
+			// 2026-10-25 柏林结束夏令时，当周多出 1 小时
+			name:      "fall back week",
+			cfg:       Config{Week: "2026-W43"},
+			loc:       berlin,
+			wantSince: time.Date(2026, 10, 18, 22, 0, 0, 0, time.UTC),
+			wantUntil: time.Date(2026, 10, 25, 23, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantLen:   7*24*time.Hour + time.Hour - time.Nanosecond,
+		},
+		{
+			name:      "fall back month",
+			cfg:       Config{Month: "2026-10"},
+			loc:       berlin,
+			wantSince: time.Date(2026, 9, 30, 22, 0, 0, 0, time.UTC),
+			wantUntil: time.Date(2026, 10, 31, 23, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantLen:   31*24*time.Hour + time.Hour - time.Nanosecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 11, 1, 12, 0, 0, 0, tt.loc)
+			got, err := resolveWindow(&tt.cfg, ReportWeekly, now)
+			if err != nil {
+				t.Fatalf("resolveWindow() error = %v", err)
+			}
+			if !got.Since.Equal(tt.wantSince) {
+				t.Errorf("Since = %v, want %v", got.Since.UTC(), tt.wantSince)
+			}
+			if !got.Until.Equal(tt.wantUntil) {
+				t.Errorf("Until = %v, want %v", got.Until.UTC(), tt.wantUntil)
+			}
+			if d := got.Until.Sub(got.Since); d != tt.wantLen {
+				t.Errorf("window length = %v, want %v", d, tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cacheEntry 是磁盘缓存中保存的一条 HTTP 响应。
+type cacheEntry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+}
+
+// cacheTransport 为 REST GET 请求提供基于磁盘的 HTTP 缓存。
+// GraphQL 请求（POST）不缓存：GraphQL API 不支持 ETag 条件请求，无法在不消耗配额的前提下确认缓存是否过期，
+// 因此默认通过 GraphQL 获取的 PR、Review 和 Projects v2 数据每次都会重新请求。
+//
+// 命中缓存时携带 If-None-Match / If-Modified-Since 发起条件请求，
+// 服务端返回 304 时直接使用缓存的响应体（304 响应不消耗 GitHub API 配额）。
+// 缓存键由 URL 和 Authorization 头的摘要组成，不同 Token 之间互不共享。
+type cacheTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// newCacheTransport 创建一个将缓存写入 dir 的 cacheTransport。
+func newCacheTransport(dir string, next http.RoundTripper) *cacheTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cacheTransport{dir: dir, next: next}
+}
+
+// RoundTrip 实现 http.RoundTripper。
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+	entry, _ := readCacheEntry(path)
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lm := entry.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		return entry.response(req, resp.Header), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || (resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// 写入失败不影响本次请求
+	_ = writeCacheEntry(path, &cacheEntry{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   time.Now(),
+	})
+	return resp, nil
+}
+
+// entryPath 返回请求对应的缓存文件路径。
+func (t *cacheTransport) entryPath(req *http.Request) string {
+	h := sha256.New()
+	io.WriteString(h, req.URL.String())
+	h.Write([]byte{0})
+	io.WriteString(h, req.Header.Get("Authorization"))
+	io.WriteString(h, req.Header.Get("Accept"))
+	key := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(t.dir, key[:2], key+".json")
+}
+
+// response 将缓存条目还原为 HTTP 响应。
+// 304 响应中的头（如 X-RateLimit-*）覆盖缓存中的同名头，以便调用方获取最新的配额信息。
+func (e *cacheEntry) response(req *http.Request, fresh http.Header) *http.Response {
+	header := e.Header.Clone()
+	for k, v := range fresh {
+		header[k] = v
+	}
+	// go-github 对带有该头的响应不更新本地速率限制状态
+	header.Set("X-From-Cache", "1")
+
+	return &http.Response{
+		Status:        http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// readCacheEntry 读取缓存文件，文件不存在或损坏时返回错误。
+func readCacheEntry(path string) (*cacheEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entry cacheEntry
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// writeCacheEntry 先写入临时文件再重命名，避免并发读取到不完整的缓存文件。
+func writeCacheEntry(path string, entry *cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// DefaultCacheDir 返回默认的 HTTP 缓存目录（用户缓存目录下的 gh-report/http）。
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-report", "http"), nil
+}
+
+// CacheStats 表示缓存目录的统计信息。
+type CacheStats struct {
+	Entries int       // 缓存条目数
+	Bytes   int64     // 占用磁盘空间（字节）
+	Oldest  time.Time // 最早写入时间
+	Newest  time.Time // 最近写入时间
+}
+
+// ReadCacheStats 统计缓存目录中的条目数和占用空间，只统计缓存自身的文件。目录不存在时返回零值。
+func ReadCacheStats(dir string) (CacheStats, error) {
+	var stats CacheStats
+	files, err := cacheFiles(dir)
+	if err != nil {
+		return stats, err
+	}
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return stats, err
+		}
+		if strings.HasPrefix(filepath.Base(path), ".tmp-") {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if mod := info.ModTime(); stats.Oldest.IsZero() || mod.Before(stats.Oldest) {
+			stats.Oldest = mod
+		}
+		if mod := info.ModTime(); mod.After(stats.Newest) {
+			stats.Newest = mod
+		}
+	}
+	return stats, nil
+}
+
+// ClearCache 删除缓存目录中缓存自身的文件（分片目录 "??/" 下的缓存条目和未完成的临时文件），
+// 再删除变空的分片目录和缓存目录。目录中的其他文件不受影响，即使 cache_dir 被误设为其他目录也不会误删。
+func ClearCache(dir string) error {
+	files, err := cacheFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	// 只删除空目录，非空时 os.Remove 失败，忽略即可
+	shards, _ := filepath.Glob(filepath.Join(dir, "??"))
+	for _, shard := range shards {
+		if isCacheShard(filepath.Base(shard)) {
+			_ = os.Remove(shard)
+		}
+	}
+	_ = os.Remove(dir)
+	return nil
+}
+
+// cacheFiles 返回缓存目录中缓存自身的文件：分片目录（键的前两位十六进制字符）下
+// 以缓存键命名的条目（"<64 位十六进制>.json"）和写入时的临时文件（".tmp-*"）。
+func cacheFiles(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "??", "*"))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, path := range matches {
+		shard, name := filepath.Base(filepath.Dir(path)), filepath.Base(path)
+		if !isCacheShard(shard) {
+			continue
+		}
+		key, ok := strings.CutSuffix(name, ".json")
+		if (ok && len(key) == sha256.Size*2 && strings.HasPrefix(key, shard) && isHex(key)) || strings.HasPrefix(name, ".tmp-") {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// isCacheShard 判断目录名是否为缓存分片目录（两位小写十六进制字符）。
+func isCacheShard(name string) bool {
+	return len(name) == 2 && isHex(name)
+}
+
+// isHex 判断字符串是否只包含小写十六进制字符。
+func isHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
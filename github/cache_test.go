@@ -0,0 +1,242 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// cacheGet 通过 client 发送带指定请求头的 GET 请求，返回响应和响应体。
+func cacheGet(t *testing.T, client *http.Client, url string, header map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestCacheTransportETagRevalidation(t *testing.T) {
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.Header().Set("X-RateLimit-Remaining", "4998")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newCacheTransport(t.TempDir(), nil)}
+
+	resp, body := cacheGet(t, client, srv.URL+"/repos/o/r", nil)
+	if resp.StatusCode != http.StatusOK || body != `{"id":1}` {
+		t.Fatalf("first response = %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("X-From-Cache") != "" {
+		t.Errorf("first response marked as cached")
+	}
+
+	// 第二次请求携带 If-None-Match，服务端返回 304 时重放缓存的响应
+	resp, body = cacheGet(t, client, srv.URL+"/repos/o/r", nil)
+	if notModified.Load() != 1 {
+		t.Fatalf("revalidation requests = %d, want 1", notModified.Load())
+	}
+	if resp.StatusCode != http.StatusOK || body != `{"id":1}` {
+		t.Errorf("replayed response = %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("X-From-Cache") != "1" {
+		t.Errorf("X-From-Cache = %q, want 1", resp.Header.Get("X-From-Cache"))
+	}
+	if resp.Header.Get("ETag") != `"v1"` {
+		t.Errorf("ETag = %q, want cached value", resp.Header.Get("ETag"))
+	}
+	// 304 响应中的配额头覆盖缓存中的旧值
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "4998" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 4998", got)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2", requests.Load())
+	}
+}
+
+func TestCacheTransportLastModified(t *testing.T) {
+	const lastModified = "Wed, 14 Oct 2026 08:00:00 GMT"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newCacheTransport(t.TempDir(), nil)}
+	cacheGet(t, client, srv.URL, nil)
+	resp, body := cacheGet(t, client, srv.URL, nil)
+	if resp.Header.Get("X-From-Cache") != "1" || body != "body" {
+		t.Errorf("response = %q (X-From-Cache=%q), want cached body", body, resp.Header.Get("X-From-Cache"))
+	}
+}
+
+func TestCacheTransportKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  map[string]string
+		second map[string]string
+		shared bool
+	}{
+		{
+			name:   "same headers",
+			first:  map[string]string{"Authorization": "Bearer a", "Accept": "application/json"},
+			second: map[string]string{"Authorization": "Bearer a", "Accept": "application/json"},
+			shared: true,
+		},
+		{
+			name:   "different token",
+			first:  map[string]string{"Authorization": "Bearer a"},
+			second: map[string]string{"Authorization": "Bearer b"},
+		},
+		{
+			name:   "different accept",
+			first:  map[string]string{"Authorization": "Bearer a", "Accept": "application/json"},
+			second: map[string]string{"Authorization": "Bearer a", "Accept": "application/vnd.github.text-match+json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revalidated atomic.Bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("If-None-Match") != "" {
+					revalidated.Store(true)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				w.Write([]byte("body"))
+			}))
+			defer srv.Close()
+
+			dir := t.TempDir()
+			client := &http.Client{Transport: newCacheTransport(dir, nil)}
+			cacheGet(t, client, srv.URL, tt.first)
+			cacheGet(t, client, srv.URL, tt.second)
+
+			if revalidated.Load() != tt.shared {
+				t.Errorf("second request revalidated = %v, want %v", revalidated.Load(), tt.shared)
+			}
+			files, err := cacheFiles(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantFiles := 2
+			if tt.shared {
+				wantFiles = 1
+			}
+			if len(files) != wantFiles {
+				t.Errorf("cache entries = %d, want %d", len(files), wantFiles)
+			}
+		})
+	}
+}
+
+func TestCacheTransportSkipsUncacheable(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		etag   string
+		status int
+	}{
+		{name: "post", method: http.MethodPost, etag: `"v1"`, status: http.StatusOK},
+		{name: "no validator", method: http.MethodGet, status: http.StatusOK},
+		{name: "error status", method: http.MethodGet, etag: `"v1"`, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("If-None-Match") != "" {
+					t.Errorf("unexpected conditional request")
+				}
+				if tt.etag != "" {
+					w.Header().Set("ETag", tt.etag)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			dir := t.TempDir()
+			client := &http.Client{Transport: newCacheTransport(dir, nil)}
+			for range 2 {
+				req, _ := http.NewRequest(tt.method, srv.URL, nil)
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+			}
+			if files, _ := cacheFiles(dir); len(files) != 0 {
+				t.Errorf("cache entries = %d, want 0", len(files))
+			}
+		})
+	}
+}
+
+func TestClearCacheKeepsForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	key := "ab" + strings.Repeat("0", 62)
+	entry := filepath.Join(dir, "ab", key+".json")
+	foreign := []string{
+		filepath.Join(dir, "notes.txt"),
+		filepath.Join(dir, "ab", "notes.json"),
+		filepath.Join(dir, "src", key+".json"),
+	}
+	for _, path := range append([]string{entry}, foreign...) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := ReadCacheStats(dir)
+	if err != nil || stats.Entries != 1 {
+		t.Fatalf("ReadCacheStats() = %+v, %v, want 1 entry", stats, err)
+	}
+	if err := ClearCache(dir); err != nil {
+		t.Fatalf("ClearCache() error = %v", err)
+	}
+	if _, err := os.Stat(entry); !os.IsNotExist(err) {
+		t.Errorf("cache entry not removed: %v", err)
+	}
+	for _, path := range foreign {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("foreign file %s removed: %v", path, err)
+		}
+	}
+}
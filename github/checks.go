@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"strings"
+
+	gh "github.com/google/go-github/v69/github"
+)
+
+// CI 汇总状态。
+const (
+	CISuccess = "success"
+	CIFailure = "failure"
+	CIPending = "pending"
+)
+
+// CIStatus 表示提交的 CI 汇总状态，合并了 Commit Status 和 Check Run 的结果。
+type CIStatus struct {
+	State   string   // CISuccess、CIFailure 或 CIPending，没有任何检查时为空
+	Failing []string // 失败的检查名称
+}
+
+// GetCIStatus 通过 REST 接口获取提交 ref 的 CI 汇总状态，合并 Combined Status 和 Check Runs。
+func (c *Client) GetCIStatus(ctx context.Context, owner, repo, ref string) (CIStatus, error) {
+	ctx = restContext(ctx)
+	var rollup ciRollup
+
+	statusOpts := &gh.ListOptions{PerPage: 100}
+	for {
+		combined, resp, err := c.REST.Repositories.GetCombinedStatus(ctx, owner, repo, ref, statusOpts)
+		if err != nil {
+			return CIStatus{}, err
+		}
+		for _, s := range combined.Statuses {
+			rollup.add(s.GetContext(), statusResult(s.GetState()))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		statusOpts.Page = resp.NextPage
+	}
+
+	checkOpts := &gh.ListCheckRunsOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := c.REST.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, checkOpts)
+		if err != nil {
+			return CIStatus{}, err
+		}
+		for _, run := range runs.CheckRuns {
+			rollup.add(run.GetName(), checkRunResult(run.GetStatus(), run.GetConclusion()))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		checkOpts.Page = resp.NextPage
+	}
+
+	return rollup.status(), nil
+}
+
+// ciRollup 汇总多个检查的结果：任一失败即为失败，否则任一未完成即为进行中。
+type ciRollup struct {
+	any     bool
+	pending bool
+	failing []string
+	seen    map[string]bool
+}
+
+// add 记录一个检查的结果，result 为 CISuccess、CIFailure 或 CIPending。
+func (r *ciRollup) add(name, result string) {
+	r.any = true
+	switch result {
+	case CIPending:
+		r.pending = true
+	case CIFailure:
+		if r.seen == nil {
+			r.seen = make(map[string]bool)
+		}
+		if !r.seen[name] {
+			r.seen[name] = true
+			r.failing = append(r.failing, name)
+		}
+	}
+}
+
+// status 返回汇总后的 CI 状态。
+func (r ciRollup) status() CIStatus {
+	switch {
+	case !r.any:
+		return CIStatus{}
+	case len(r.failing) > 0:
+		return CIStatus{State: CIFailure, Failing: r.failing}
+	case r.pending:
+		return CIStatus{State: CIPending}
+	default:
+		return CIStatus{State: CISuccess}
+	}
+}
+
+// statusResult 将 Commit Status 的状态（success、pending、failure、error）转换为检查结果。
+// GraphQL 返回的大写状态同样适用。
+func statusResult(state string) string {
+	switch strings.ToLower(state) {
+	case "success":
+		return CISuccess
+	case "failure", "error":
+		return CIFailure
+	default:
+		return CIPending
+	}
+}
+
+// checkRunResult 将 Check Run 的状态和结论转换为检查结果。
+// 未完成的视为进行中；neutral、skipped 等不影响合并的结论视为成功。GraphQL 返回的大写值同样适用。
+func checkRunResult(status, conclusion string) string {
+	if !strings.EqualFold(status, "completed") {
+		return CIPending
+	}
+	switch strings.ToLower(conclusion) {
+	case "failure", "timed_out", "cancelled", "action_required", "startup_failure":
+		return CIFailure
+	default:
+		return CISuccess
+	}
+}
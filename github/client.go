@@ -8,13 +8,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	gh "github.com/google/go-github/v69/github"
 )
 
-// graphqlURL 是 GitHub GraphQL API 的端点地址。
-const graphqlURL = "https://api.github.com/graphql"
+// DefaultHost 是 GitHub.com 的主机名。
+const DefaultHost = "github.com"
+
+// defaultGraphQLURL 是 GitHub.com GraphQL API 的端点地址。
+const defaultGraphQLURL = "https://api.github.com/graphql"
 
 // Client 封装 go-github 客户端，并提供额外的 GraphQL 支持。
 type Client struct {
@@ -23,14 +27,134 @@ type Client struct {
 	// httpClient 复用 go-github 的 HTTP 客户端（携带认证信息），用于 GraphQL 请求。
 	httpClient *http.Client
 	token      string
+	host       string        // 主机名，如 github.com 或 GitHub Enterprise Server 的主机名
+	graphqlURL string        // GraphQL API 端点地址
+	projectSem chan struct{} // 限制同时分页获取工作项的项目数
+}
+
+// Option 配置 Client 的可选项。
+type Option func(*clientOptions)
+
+// clientOptions 保存 NewClient 的可选配置。
+type clientOptions struct {
+	cacheDir    string
+	concurrency int
+	observer    func(RateLimit)
+	baseURL     string
+}
+
+// WithBaseURL 指定 GitHub Enterprise Server 的地址，如 "https://ghe.example.com" 或 "ghe.example.com"。
+// REST 请求发往 <baseURL>/api/v3/，GraphQL 请求发往 <baseURL>/api/graphql。
+// 为空或为 github.com 时使用 GitHub.com。
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithCache 启用磁盘 HTTP 缓存，REST GET 响应保存在 dir 中并通过 ETag 条件请求重新验证。
+// dir 为空时不启用缓存。
+func WithCache(dir string) Option {
+	return func(o *clientOptions) {
+		o.cacheDir = dir
+	}
+}
+
+// WithConcurrency 设置同时进行的最大请求数（包括 REST 和 GraphQL），n <= 0 时使用 DefaultConcurrency。
+func WithConcurrency(n int) Option {
+	return func(o *clientOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithRateLimitObserver 设置配额状态回调，每次收到带配额信息的响应或触发限流等待时调用。
+// 回调可能被并发调用。
+func WithRateLimitObserver(fn func(RateLimit)) Option {
+	return func(o *clientOptions) {
+		o.observer = fn
+	}
 }
 
 // NewClient 创建一个新的 GitHub API 客户端。
-func NewClient(token string) *Client {
-	c := &Client{token: token}
-	c.REST = gh.NewClient(nil).WithAuthToken(token)
+// 所有请求经过限流处理：限制并发数，触发主/次级限流时等待后自动重试。
+func NewClient(token string, opts ...Option) (*Client, error) {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var transport http.RoundTripper = newRateLimitTransport(http.DefaultTransport, o.concurrency, o.observer)
+	if o.cacheDir != "" {
+		transport = newCacheTransport(o.cacheDir, transport)
+	}
+
+	c := &Client{
+		token:      token,
+		host:       DefaultHost,
+		graphqlURL: defaultGraphQLURL,
+		projectSem: make(chan struct{}, maxProjectFetches),
+	}
+	c.REST = gh.NewClient(&http.Client{Transport: transport}).WithAuthToken(token)
+
+	base, err := normalizeBaseURL(o.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if base != nil {
+		rest, err := c.REST.WithEnterpriseURLs(base.String()+"/api/v3/", base.String()+"/api/uploads/")
+		if err != nil {
+			return nil, err
+		}
+		c.REST = rest
+		c.host = base.Host
+		c.graphqlURL = base.String() + "/api/graphql"
+	}
+
 	c.httpClient = c.REST.Client()
-	return c
+	return c, nil
+}
+
+// Host 返回客户端连接的主机名。
+func (c *Client) Host() string {
+	return c.host
+}
+
+// normalizeBaseURL 解析 GitHub Enterprise Server 地址，返回不带路径的 scheme://host。
+// 缺省 scheme 时使用 https，末尾的 /api/v3 会被去掉。为空或指向 GitHub.com 时返回 nil。
+func normalizeBaseURL(s string) (*url.URL, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("无效的 GitHub 地址 %q", s)
+	}
+	if IsDefaultHost(u.Host) {
+		return nil, nil
+	}
+	return &url.URL{Scheme: u.Scheme, Host: u.Host}, nil
+}
+
+// HostOf 返回 GitHub 地址对应的主机名，为空或指向 GitHub.com 时返回 DefaultHost。
+func HostOf(baseURL string) (string, error) {
+	u, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return "", err
+	}
+	if u == nil {
+		return DefaultHost, nil
+	}
+	return u.Host, nil
+}
+
+// IsDefaultHost 判断主机名是否指向 GitHub.com。
+func IsDefaultHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "" || host == DefaultHost || host == "api."+DefaultHost || host == "www."+DefaultHost
 }
 
 // graphqlRequest 表示 GraphQL 请求体。
@@ -44,9 +168,31 @@ type graphqlResponse struct {
 	Data   json.RawMessage `json:"data"`
 	Errors []struct {
 		Message string `json:"message"`
+		Type    string `json:"type"`
 	} `json:"errors"`
 }
 
+// GraphQLError 表示 GraphQL 响应中返回的错误。
+type GraphQLError struct {
+	Messages []string // 错误信息
+	Types    []string // 错误类型（如 NOT_FOUND），与 Messages 一一对应，部分错误没有类型
+}
+
+// Error 实现 error 接口。
+func (e *GraphQLError) Error() string {
+	return "GraphQL errors: " + strings.Join(e.Messages, "; ")
+}
+
+// NotFound 判断错误是否为查询的对象不存在（如按组织查询一个用户账号）。
+func (e *GraphQLError) NotFound() bool {
+	for _, t := range e.Types {
+		if t == "NOT_FOUND" {
+			return true
+		}
+	}
+	return false
+}
+
 // GraphQL 执行 GitHub GraphQL API 查询，将结果解码到 result 中。
 func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, result any) error {
 	body, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
@@ -54,7 +200,7 @@ func (c *Client) GraphQL(ctx context.Context, query string, variables map[string
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", graphqlURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.graphqlURL, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
@@ -77,11 +223,12 @@ func (c *Client) GraphQL(ctx context.Context, query string, variables map[string
 	}
 
 	if len(gqlResp.Errors) > 0 {
-		msgs := make([]string, len(gqlResp.Errors))
-		for i, e := range gqlResp.Errors {
-			msgs[i] = e.Message
+		gqlErr := &GraphQLError{}
+		for _, e := range gqlResp.Errors {
+			gqlErr.Messages = append(gqlErr.Messages, e.Message)
+			gqlErr.Types = append(gqlErr.Types, e.Type)
 		}
-		return fmt.Errorf("GraphQL errors: %s", strings.Join(msgs, "; "))
+		return gqlErr
 	}
 
 	return json.Unmarshal(gqlResp.Data, result)
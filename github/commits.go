@@ -0,0 +1,185 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v69/github"
+)
+
+// ListCommits 获取仓库默认分支上自指定时间以来的提交。author 不为空时只返回该用户的提交。
+func (c *Client) ListCommits(ctx context.Context, owner, repo string, since time.Time, author string) ([]*gh.RepositoryCommit, error) {
+	return c.ListBranchCommits(ctx, owner, repo, "", since, author)
+}
+
+// ListBranchCommits 获取仓库指定分支上自指定时间以来的提交，branch 为空时使用默认分支。
+func (c *Client) ListBranchCommits(ctx context.Context, owner, repo, branch string, since time.Time, author string) ([]*gh.RepositoryCommit, error) {
+	ctx = restContext(ctx)
+	opts := &gh.CommitsListOptions{
+		SHA:         branch,
+		Author:      author,
+		Since:       since,
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+
+	var all []*gh.RepositoryCommit
+	for {
+		commits, resp, err := c.REST.Repositories.ListCommits(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, commits...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// branchesQuery 分页获取仓库的分支及每个分支最新提交的提交时间。
+const branchesQuery = `
+query($owner: String!, $repo: String!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    defaultBranchRef { name }
+    refs(refPrefix: "refs/heads/", first: 100, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        target { ... on Commit { committedDate } }
+      }
+    }
+  }
+}
+`
+
+// gqlBranchesResponse 是分支查询的响应结构。
+type gqlBranchesResponse struct {
+	Repository struct {
+		DefaultBranchRef *struct {
+			Name string `json:"name"`
+		} `json:"defaultBranchRef"`
+		Refs struct {
+			PageInfo gqlPageInfo `json:"pageInfo"`
+			Nodes    []struct {
+				Name   string `json:"name"`
+				Target struct {
+					CommittedDate time.Time `json:"committedDate"`
+				} `json:"target"`
+			} `json:"nodes"`
+		} `json:"refs"`
+	} `json:"repository"`
+}
+
+// Branch 表示仓库的一个分支。
+type Branch struct {
+	Name        string
+	Default     bool      // 是否为默认分支
+	CommittedAt time.Time // 分支最新提交的提交时间
+}
+
+// ListBranches 通过 GraphQL 分页获取仓库的全部分支及其最新提交时间，每页 100 个分支只需一次请求。
+// 空仓库没有分支，返回空列表。
+func (c *Client) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	var branches []Branch
+	var cursor *string
+	for {
+		vars := map[string]any{"owner": owner, "repo": repo}
+		if cursor != nil {
+			vars["cursor"] = *cursor
+		}
+
+		var resp gqlBranchesResponse
+		if err := c.GraphQL(ctx, branchesQuery, vars, &resp); err != nil {
+			return nil, fmt.Errorf("fetching branches for %s/%s: %w", owner, repo, err)
+		}
+
+		defaultBranch := ""
+		if resp.Repository.DefaultBranchRef != nil {
+			defaultBranch = resp.Repository.DefaultBranchRef.Name
+		}
+		for _, node := range resp.Repository.Refs.Nodes {
+			branches = append(branches, Branch{
+				Name:        node.Name,
+				Default:     node.Name == defaultBranch,
+				CommittedAt: node.Target.CommittedDate,
+			})
+		}
+
+		if !resp.Repository.Refs.PageInfo.HasNextPage {
+			break
+		}
+		next := resp.Repository.Refs.PageInfo.EndCursor
+		cursor = &next
+	}
+	return branches, nil
+}
+
+// commitPRBatchSize 是每次 GraphQL 请求查询关联 PR 的提交数。
+const commitPRBatchSize = 50
+
+// gqlCommitPullRequests 是单个提交关联 PR 的 GraphQL 响应结构。
+type gqlCommitPullRequests struct {
+	AssociatedPullRequests struct {
+		Nodes []struct {
+			Number int `json:"number"`
+		} `json:"nodes"`
+	} `json:"associatedPullRequests"`
+}
+
+// ListCommitsPullRequests 批量获取包含指定提交的 Pull Request 编号，以提交 SHA 为键。
+// 通过 GraphQL 的 associatedPullRequests 查询，每次请求最多 commitPRBatchSize 个提交，每个提交取前 10 个 PR；
+// 不是合法 SHA 或仓库中不存在的提交不出现在结果中。
+func (c *Client) ListCommitsPullRequests(ctx context.Context, owner, repo string, shas []string) (map[string][]int, error) {
+	var valid []string
+	for _, sha := range shas {
+		if isCommitSHA(sha) {
+			valid = append(valid, sha)
+		}
+	}
+
+	result := make(map[string][]int, len(valid))
+	for start := 0; start < len(valid); start += commitPRBatchSize {
+		batch := valid[start:min(start+commitPRBatchSize, len(valid))]
+
+		var sb strings.Builder
+		sb.WriteString("query($owner: String!, $repo: String!) {\n  repository(owner: $owner, name: $repo) {\n")
+		for i, sha := range batch {
+			fmt.Fprintf(&sb, "    c%d: object(oid: %q) { ... on Commit { associatedPullRequests(first: 10) { nodes { number } } } }\n", i, sha)
+		}
+		sb.WriteString("  }\n}\n")
+
+		var resp struct {
+			Repository map[string]*gqlCommitPullRequests `json:"repository"`
+		}
+		vars := map[string]any{"owner": owner, "repo": repo}
+		if err := c.GraphQL(ctx, sb.String(), vars, &resp); err != nil {
+			return nil, fmt.Errorf("fetching pull requests for commits in %s/%s: %w", owner, repo, err)
+		}
+		for i, sha := range batch {
+			commit := resp.Repository[fmt.Sprintf("c%d", i)]
+			if commit == nil {
+				continue
+			}
+			for _, pr := range commit.AssociatedPullRequests.Nodes {
+				result[sha] = append(result[sha], pr.Number)
+			}
+		}
+	}
+	return result, nil
+}
+
+// isCommitSHA 判断 s 是否为 40 位十六进制的完整提交 SHA。
+func isCommitSHA(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
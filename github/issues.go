@@ -10,6 +10,7 @@ import (
 // ListIssues 获取仓库中自指定时间以来有更新的 Issue 列表。
 // 返回结果中包含 Pull Request（可通过 IsPullRequest 方法区分）。
 func (c *Client) ListIssues(ctx context.Context, owner, repo string, since time.Time) ([]*gh.Issue, error) {
+	ctx = restContext(ctx)
 	opts := &gh.IssueListByRepoOptions{
 		State: "all",
 		Since: since,
@@ -35,6 +36,7 @@ func (c *Client) ListIssues(ctx context.Context, owner, repo string, since time.
 // ListIssueComments 获取仓库中自指定时间以来的所有 Issue 评论。
 // 包括 Issue 和 Pull Request 上的普通评论。
 func (c *Client) ListIssueComments(ctx context.Context, owner, repo string, since time.Time) ([]*gh.IssueComment, error) {
+	ctx = restContext(ctx)
 	opts := &gh.IssueListCommentsOptions{
 		Since: &since,
 		Sort:  gh.String("updated"),
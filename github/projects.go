@@ -3,17 +3,109 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
-// projectsQuery 获取组织下的 Projects v2 列表及迭代字段配置（不含工作项）。
-const projectsQuery = `
-query($org: String!, $cursor: String) {
-  organization(login: $org) {
-    projectsV2(first: 20, after: $cursor) {
-      pageInfo { hasNextPage endCursor }
-      nodes {
+// defaultStatusField 是未指定状态字段时使用的单选字段名。
+const defaultStatusField = "Status"
+
+// maxProjectFetches 是每个客户端同时分页获取工作项的最大项目数。
+const maxProjectFetches = 4
+
+// ProjectOptions 控制 Projects v2 项目的选择和字段解析。
+type ProjectOptions struct {
+	// Include 按编号或标题（不区分大小写）选择所有者下的项目，为空时获取全部。只作用于 ListProjects。
+	Include []string
+	// IterationField 迭代字段名，为空时使用项目中的所有迭代字段。
+	// 为空且项目只有一个迭代字段时，按该字段在服务端筛选迭代；有多个迭代字段时不按迭代筛选，
+	// 分页获取全部工作项后在本地过滤。
+	IterationField string
+	// StatusField 状态字段名（单选字段），为空时使用 "Status"。
+	StatusField string
+
+	// Repos 只获取这些仓库（客户端所在主机上的 "owner/repo"）中的工作项，为空时获取全部。
+	// 优先通过项目筛选语法（repo:）在服务端过滤，服务端不支持时在本地过滤。
+	Repos []string
+	// Until 迭代的参考时间。非零时只获取与之相关的迭代（Previous、Current、Next）中的工作项，
+	// 没有相关迭代的项目不获取工作项。
+	Until time.Time
+	// SkipItems 只获取项目及迭代配置，不获取工作项（如确定迭代报告的时间范围）。
+	SkipItems bool
+}
+
+// includes 判断项目是否被 Include 选中。
+func (o ProjectOptions) includes(p Project) bool {
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, s := range o.Include {
+		s = strings.TrimSpace(s)
+		if s == strconv.Itoa(p.Number) || strings.EqualFold(s, p.Title) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusField 返回状态字段名。
+func (o ProjectOptions) statusField() string {
+	if o.StatusField == "" {
+		return defaultStatusField
+	}
+	return o.StatusField
+}
+
+// itemQuery 返回工作项查询的项目筛选语法，如 `repo:acme/api,acme/web sprint:"Sprint 3","Sprint 4"`。
+// 只有迭代字段名 field 非空且不含空白时才按迭代筛选；titles 为相关迭代的标题。
+func (o ProjectOptions) itemQuery(field string, titles []string) string {
+	var filters []string
+	if len(o.Repos) > 0 {
+		filters = append(filters, "repo:"+strings.Join(o.Repos, ","))
+	}
+	if len(titles) > 0 && field != "" && !strings.ContainsAny(field, " \t\"") {
+		quoted := make([]string, 0, len(titles))
+		for _, t := range titles {
+			if strings.Contains(t, `"`) {
+				return strings.Join(filters, " ")
+			}
+			quoted = append(quoted, strconv.Quote(t))
+		}
+		filters = append(filters, strings.ToLower(field)+":"+strings.Join(quoted, ","))
+	}
+	return strings.Join(filters, " ")
+}
+
+// inRepos 判断工作项是否属于主机 host 上 Repos 中的仓库，Repos 为空时始终为 true。
+func (o ProjectOptions) inRepos(item ProjectItem, host string) bool {
+	if len(o.Repos) == 0 {
+		return true
+	}
+	for _, repo := range o.Repos {
+		if URLInRepo(item.URL, host+"/"+repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIterationField 判断迭代字段是否为选定的迭代字段。
+func (o ProjectOptions) isIterationField(name string) bool {
+	return o.IterationField == "" || strings.EqualFold(name, o.IterationField)
+}
+
+// 项目所有者的 GraphQL 字段名。
+const (
+	ownerOrganization = "organization"
+	ownerUser         = "user"
+)
+
+// projectFields 是项目查询共用的字段：基本信息及迭代字段配置（不含工作项）。
+const projectFields = `
         id
         title
         number
@@ -39,18 +131,36 @@ query($org: String!, $cursor: String) {
             }
           }
         }
-      }
+`
+
+// projectsQuery 获取组织或用户（%s 为 organization 或 user）下的 Projects v2 列表。
+const projectsQuery = `
+query($owner: String!, $cursor: String) {
+  %s(login: $owner) {
+    projectsV2(first: 20, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      nodes {` + projectFields + `      }
     }
   }
 }
 `
 
+// projectQuery 按编号获取组织或用户（%s 为 organization 或 user）下的单个 Projects v2 项目。
+const projectQuery = `
+query($owner: String!, $number: Int!) {
+  %s(login: $owner) {
+    projectV2(number: $number) {` + projectFields + `    }
+  }
+}
+`
+
 // projectItemsQuery 分页获取单个 Project 的工作项。
+// 两个 %s 分别为筛选变量声明和 items 的 query 参数，不筛选时均为空。
 const projectItemsQuery = `
-query($projectID: ID!, $cursor: String) {
+query($projectID: ID!, $cursor: String%s) {
   node(id: $projectID) {
     ... on ProjectV2 {
-      items(first: 100, after: $cursor) {
+      items(first: 100, after: $cursor%s) {
         pageInfo { hasNextPage endCursor }
         nodes {
           content {
@@ -59,6 +169,7 @@ query($projectID: ID!, $cursor: String) {
               number
               url
               state
+              closedAt
               assignees(first: 10) { nodes { login } }
             }
             ... on PullRequest {
@@ -66,18 +177,35 @@ query($projectID: ID!, $cursor: String) {
               number
               url
               state
+              closedAt
+              mergedAt
               assignees(first: 10) { nodes { login } }
             }
           }
           type
-          fieldValues(first: 20) {
+          fieldValues(first: 30) {
             nodes {
+              __typename
               ... on ProjectV2ItemFieldIterationValue {
                 title
                 iterationId
+                field { ... on ProjectV2FieldCommon { name dataType } }
               }
               ... on ProjectV2ItemFieldSingleSelectValue {
                 name
+                field { ... on ProjectV2FieldCommon { name dataType } }
+              }
+              ... on ProjectV2ItemFieldTextValue {
+                text
+                field { ... on ProjectV2FieldCommon { name dataType } }
+              }
+              ... on ProjectV2ItemFieldNumberValue {
+                number
+                field { ... on ProjectV2FieldCommon { name dataType } }
+              }
+              ... on ProjectV2ItemFieldDateValue {
+                date
+                field { ... on ProjectV2FieldCommon { name dataType } }
               }
             }
           }
@@ -88,14 +216,30 @@ query($projectID: ID!, $cursor: String) {
 }
 `
 
-// gqlProjectsResponse 是 Projects 列表查询的响应结构。
+// gqlProjectsResponse 是 Projects 查询的响应结构，按查询的所有者类型填充其中一个字段。
 type gqlProjectsResponse struct {
-	Organization struct {
-		ProjectsV2 struct {
-			PageInfo gqlPageInfo  `json:"pageInfo"`
-			Nodes    []gqlProject `json:"nodes"`
-		} `json:"projectsV2"`
-	} `json:"organization"`
+	Organization *gqlProjectOwner `json:"organization"`
+	User         *gqlProjectOwner `json:"user"`
+}
+
+// gqlProjectOwner 是项目所有者（组织或用户）的响应结构。
+type gqlProjectOwner struct {
+	ProjectsV2 struct {
+		PageInfo gqlPageInfo  `json:"pageInfo"`
+		Nodes    []gqlProject `json:"nodes"`
+	} `json:"projectsV2"`
+	ProjectV2 *gqlProject `json:"projectV2"`
+}
+
+// owner 返回响应中的项目所有者，所有者不存在时返回空结构。
+func (r gqlProjectsResponse) owner() gqlProjectOwner {
+	switch {
+	case r.Organization != nil:
+		return *r.Organization
+	case r.User != nil:
+		return *r.User
+	}
+	return gqlProjectOwner{}
 }
 
 // gqlProjectItemsResponse 是单个 Project 工作项查询的响应结构。
@@ -140,7 +284,9 @@ type gqlProjectItem struct {
 		Title     string `json:"title"`
 		Number    int    `json:"number"`
 		URL       string `json:"url"`
-		State     string `json:"state"`
+		State     string     `json:"state"`
+		ClosedAt  *time.Time `json:"closedAt"`
+		MergedAt  *time.Time `json:"mergedAt"`
 		Assignees struct {
 			Nodes []struct {
 				Login string `json:"login"`
@@ -149,72 +295,185 @@ type gqlProjectItem struct {
 	} `json:"content"`
 	Type        string `json:"type"`
 	FieldValues struct {
-		Nodes []json.RawMessage `json:"nodes"`
+		Nodes []gqlFieldValue `json:"nodes"`
 	} `json:"fieldValues"`
 }
 
-// ListProjects 获取指定组织下的所有 Projects v2 项目，包含迭代和工作项数据。
-// 先获取项目列表及迭代配置，再对每个项目分页获取全部工作项。
-func (c *Client) ListProjects(ctx context.Context, org string) ([]Project, error) {
+// gqlFieldValue 是工作项字段值的 GraphQL 响应结构，按 __typename 区分字段类型。
+type gqlFieldValue struct {
+	Typename    string   `json:"__typename"`
+	Title       string   `json:"title"`       // 迭代
+	IterationID string   `json:"iterationId"` // 迭代
+	Name        string   `json:"name"`        // 单选
+	Text        string   `json:"text"`        // 文本
+	Number      *float64 `json:"number"`      // 数字
+	Date        string   `json:"date"`        // 日期
+	Field       struct {
+		Name     string `json:"name"`
+		DataType string `json:"dataType"` // TEXT、NUMBER、DATE、SINGLE_SELECT、ITERATION、TITLE 等
+	} `json:"field"`
+}
+
+// projectMeta 是尚未获取工作项的项目。
+type projectMeta struct {
+	id             string
+	project        Project
+	iterationField string // 用于服务端筛选迭代的字段名，选定的迭代字段不唯一时为空
+}
+
+// ListProjects 获取指定组织或用户下的 Projects v2 项目，包含迭代和工作项数据。
+// 先按组织查询，owner 不是组织时回退到按用户查询（个人项目）。
+// 先获取项目列表及迭代配置，按 opts.Include 选择项目后，再对每个项目分页获取全部工作项。
+func (c *Client) ListProjects(ctx context.Context, owner string, opts ProjectOptions) ([]Project, error) {
 	// 第一步：获取所有项目及其迭代字段
-	type projectMeta struct {
-		id     string
-		project Project
+	metas, err := c.listProjectMetas(ctx, ownerOrganization, owner, opts)
+	if isNotFound(err) {
+		metas, err = c.listProjectMetas(ctx, ownerUser, owner, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching projects for %s: %w", owner, err)
 	}
+
+	selected := metas[:0]
+	for _, m := range metas {
+		if opts.includes(m.project) {
+			selected = append(selected, m)
+		}
+	}
+
+	// 第二步：并发获取每个项目的全部工作项
+	return c.loadProjectItems(ctx, selected, opts)
+}
+
+// GetProject 按编号获取组织或用户下的单个 Projects v2 项目，包含迭代和工作项数据。
+// 与 ListProjects 一样，owner 不是组织时回退到按用户查询。opts.Include 不影响指定编号的项目。
+func (c *Client) GetProject(ctx context.Context, owner string, number int, opts ProjectOptions) (Project, error) {
+	meta, err := c.getProjectMeta(ctx, ownerOrganization, owner, number, opts)
+	if isNotFound(err) {
+		meta, err = c.getProjectMeta(ctx, ownerUser, owner, number, opts)
+	}
+	if err != nil {
+		return Project{}, fmt.Errorf("fetching project %s/%d: %w", owner, number, err)
+	}
+
+	projects, err := c.loadProjectItems(ctx, []projectMeta{meta}, opts)
+	if err != nil {
+		return Project{}, err
+	}
+	return projects[0], nil
+}
+
+// listProjectMetas 分页获取所有者下的项目列表及迭代配置，ownerType 为 organization 或 user。
+func (c *Client) listProjectMetas(ctx context.Context, ownerType, owner string, opts ProjectOptions) ([]projectMeta, error) {
+	query := fmt.Sprintf(projectsQuery, ownerType)
 	var metas []projectMeta
 
 	var cursor *string
 	for {
-		vars := map[string]any{"org": org}
+		vars := map[string]any{"owner": owner}
 		if cursor != nil {
 			vars["cursor"] = *cursor
 		}
 
 		var resp gqlProjectsResponse
-		if err := c.GraphQL(ctx, projectsQuery, vars, &resp); err != nil {
-			return nil, fmt.Errorf("fetching projects for %s: %w", org, err)
+		if err := c.GraphQL(ctx, query, vars, &resp); err != nil {
+			return nil, err
 		}
 
-		for _, gp := range resp.Organization.ProjectsV2.Nodes {
-			p := Project{
-				Title:  gp.Title,
-				Number: gp.Number,
-			}
-			// 解析迭代字段
-			for _, raw := range gp.Fields.Nodes {
-				var field gqlIterationField
-				if err := json.Unmarshal(raw, &field); err != nil || field.ID == "" {
-					continue
-				}
-				allIter := append(field.Configuration.Iterations, field.Configuration.CompletedIterations...)
-				p.Iterations = append(p.Iterations, allIter...)
-			}
-			metas = append(metas, projectMeta{id: gp.ID, project: p})
+		projects := resp.owner().ProjectsV2
+		for _, gp := range projects.Nodes {
+			metas = append(metas, gp.toMeta(opts))
 		}
 
-		if !resp.Organization.ProjectsV2.PageInfo.HasNextPage {
+		if !projects.PageInfo.HasNextPage {
 			break
 		}
-		next := resp.Organization.ProjectsV2.PageInfo.EndCursor
+		next := projects.PageInfo.EndCursor
 		cursor = &next
 	}
+	return metas, nil
+}
 
-	// 第二步：并发获取每个项目的全部工作项
+// getProjectMeta 获取所有者下指定编号的项目及迭代配置，ownerType 为 organization 或 user。
+func (c *Client) getProjectMeta(ctx context.Context, ownerType, owner string, number int, opts ProjectOptions) (projectMeta, error) {
+	var resp gqlProjectsResponse
+	vars := map[string]any{"owner": owner, "number": number}
+	if err := c.GraphQL(ctx, fmt.Sprintf(projectQuery, ownerType), vars, &resp); err != nil {
+		return projectMeta{}, err
+	}
+	gp := resp.owner().ProjectV2
+	if gp == nil {
+		return projectMeta{}, fmt.Errorf("project %s/%d not found", owner, number)
+	}
+	return gp.toMeta(opts), nil
+}
+
+// toMeta 将 GraphQL 项目响应转换为 projectMeta，解析选定迭代字段的迭代。
+// 未指定迭代字段时，项目只有一个迭代字段则以该字段作为服务端筛选的字段。
+func (gp gqlProject) toMeta(opts ProjectOptions) projectMeta {
+	p := Project{
+		Title:  gp.Title,
+		Number: gp.Number,
+	}
+	var fields []string
+	for _, raw := range gp.Fields.Nodes {
+		var field gqlIterationField
+		if err := json.Unmarshal(raw, &field); err != nil || field.ID == "" || !opts.isIterationField(field.Name) {
+			continue
+		}
+		allIter := append(field.Configuration.Iterations, field.Configuration.CompletedIterations...)
+		p.Iterations = append(p.Iterations, allIter...)
+		fields = append(fields, field.Name)
+	}
+	meta := projectMeta{id: gp.ID, project: p}
+	if len(fields) == 1 {
+		meta.iterationField = fields[0]
+	}
+	return meta
+}
+
+// loadProjectItems 并发获取每个项目的工作项，同时进行的项目数不超过 maxProjectFetches。
+// 指定 opts.Until 时，没有相关迭代的项目不获取工作项，只保留相关迭代中的工作项。
+func (c *Client) loadProjectItems(ctx context.Context, metas []projectMeta, opts ProjectOptions) ([]Project, error) {
 	allProjects := make([]Project, len(metas))
+	if opts.SkipItems {
+		for i, m := range metas {
+			allProjects[i] = m.project
+		}
+		return allProjects, nil
+	}
+
 	itemErrs := make([]error, len(metas))
 	var itemsWg sync.WaitGroup
 	for i, m := range metas {
+		var titles []string
+		if !opts.Until.IsZero() {
+			titles = FindRelevantIterations(m.project.Iterations, opts.Until).Titles()
+			if len(titles) == 0 {
+				allProjects[i] = m.project
+				continue
+			}
+		}
+
+		select {
+		case c.projectSem <- struct{}{}:
+		case <-ctx.Done():
+			itemsWg.Wait()
+			return nil, ctx.Err()
+		}
 		itemsWg.Add(1)
-		go func(idx int, projectID string, project Project) {
+		go func(idx int, meta projectMeta) {
 			defer itemsWg.Done()
-			items, err := c.fetchAllProjectItems(ctx, projectID)
+			defer func() { <-c.projectSem }()
+			items, err := c.fetchAllProjectItems(ctx, meta, opts, titles)
 			if err != nil {
-				itemErrs[idx] = fmt.Errorf("fetching items for project %q: %w", project.Title, err)
+				itemErrs[idx] = fmt.Errorf("fetching items for project %q: %w", meta.project.Title, err)
 				return
 			}
+			project := meta.project
 			project.Items = items
 			allProjects[idx] = project
-		}(i, m.id, m.project)
+		}(i, m)
 	}
 	itemsWg.Wait()
 
@@ -227,27 +486,66 @@ func (c *Client) ListProjects(ctx context.Context, org string) ([]Project, error
 	return allProjects, nil
 }
 
-// fetchAllProjectItems 分页获取单个 Project 的全部工作项。
-func (c *Client) fetchAllProjectItems(ctx context.Context, projectID string) ([]ProjectItem, error) {
-	var all []ProjectItem
+// isNotFound 判断错误是否为 GraphQL 查询对象不存在。
+func isNotFound(err error) bool {
+	var gqlErr *GraphQLError
+	return errors.As(err, &gqlErr) && gqlErr.NotFound()
+}
+
+// fetchAllProjectItems 分页获取单个 Project 的工作项，titles 非空时只保留这些迭代中的工作项。
+// 先带筛选条件查询，服务端不支持 items 的 query 参数（如较旧的 GitHub Enterprise Server）时回退到不带筛选的查询。
+// 迭代筛选使用 opts.IterationField，未指定时使用项目唯一的迭代字段；项目有多个迭代字段时不在服务端按迭代筛选。
+func (c *Client) fetchAllProjectItems(ctx context.Context, meta projectMeta, opts ProjectOptions, titles []string) ([]ProjectItem, error) {
+	field := opts.IterationField
+	if field == "" {
+		field = meta.iterationField
+	}
+	filter := opts.itemQuery(field, titles)
+	if filter != "" {
+		items, err := c.fetchProjectItems(ctx, meta.id, filter, opts, titles)
+		var gqlErr *GraphQLError
+		if !errors.As(err, &gqlErr) {
+			return items, err
+		}
+	}
+	return c.fetchProjectItems(ctx, meta.id, "", opts, titles)
+}
 
+// fetchProjectItems 按筛选条件 filter 分页获取单个 Project 的工作项，并在本地按仓库和迭代再次过滤。
+func (c *Client) fetchProjectItems(ctx context.Context, projectID, filter string, opts ProjectOptions, titles []string) ([]ProjectItem, error) {
+	query := fmt.Sprintf(projectItemsQuery, "", "")
+	if filter != "" {
+		query = fmt.Sprintf(projectItemsQuery, ", $query: String!", ", query: $query")
+	}
+	relevant := make(map[string]bool, len(titles))
+	for _, t := range titles {
+		relevant[t] = true
+	}
+
+	var all []ProjectItem
 	var cursor *string
 	for {
 		vars := map[string]any{"projectID": projectID}
+		if filter != "" {
+			vars["query"] = filter
+		}
 		if cursor != nil {
 			vars["cursor"] = *cursor
 		}
 
 		var resp gqlProjectItemsResponse
-		if err := c.GraphQL(ctx, projectItemsQuery, vars, &resp); err != nil {
+		if err := c.GraphQL(ctx, query, vars, &resp); err != nil {
 			return nil, err
 		}
 
 		for _, item := range resp.Node.Items.Nodes {
-			pi := parseProjectItem(item)
+			pi := parseProjectItem(item, opts)
 			if pi.Number == 0 {
 				continue // 跳过草稿项或无内容的项
 			}
+			if !opts.inRepos(pi, c.host) || (len(relevant) > 0 && !relevant[pi.Iteration]) {
+				continue
+			}
 			all = append(all, pi)
 		}
 
@@ -262,7 +560,8 @@ func (c *Client) fetchAllProjectItems(ctx context.Context, projectID string) ([]
 }
 
 // parseProjectItem 将 GraphQL 工作项响应转换为 ProjectItem。
-func parseProjectItem(item gqlProjectItem) ProjectItem {
+// 迭代和状态只取自选定的字段，其余自定义字段放入 Fields。
+func parseProjectItem(item gqlProjectItem, opts ProjectOptions) ProjectItem {
 	pi := ProjectItem{
 		Title:  item.Content.Title,
 		Number: item.Content.Number,
@@ -270,27 +569,57 @@ func parseProjectItem(item gqlProjectItem) ProjectItem {
 		State:  item.Content.State,
 		Type:   item.Type,
 	}
+	switch {
+	case item.Content.MergedAt != nil:
+		pi.ClosedAt = *item.Content.MergedAt
+	case item.Content.ClosedAt != nil:
+		pi.ClosedAt = *item.Content.ClosedAt
+	}
 
 	for _, node := range item.Content.Assignees.Nodes {
 		pi.Assignees = append(pi.Assignees, node.Login)
 	}
 
-	// 从字段值中提取迭代标题和状态
-	for _, fvRaw := range item.FieldValues.Nodes {
-		var iterVal struct {
-			Title       string `json:"title"`
-			IterationID string `json:"iterationId"`
-		}
-		if err := json.Unmarshal(fvRaw, &iterVal); err == nil && iterVal.IterationID != "" {
-			pi.Iteration = iterVal.Title
+	statusField := opts.statusField()
+	for _, fv := range item.FieldValues.Nodes {
+		name := fv.Field.Name
+		var value ProjectFieldValue
+		switch fv.Typename {
+		case "ProjectV2ItemFieldIterationValue":
+			if pi.Iteration == "" && opts.isIterationField(name) {
+				pi.Iteration = fv.Title
+				continue
+			}
+			value = ProjectFieldValue{Type: FieldIteration, Text: fv.Title}
+		case "ProjectV2ItemFieldSingleSelectValue":
+			if strings.EqualFold(name, statusField) {
+				pi.Status = fv.Name
+				continue
+			}
+			value = ProjectFieldValue{Type: FieldSingleSelect, Text: fv.Name}
+		case "ProjectV2ItemFieldTextValue":
+			// 标题等内置字段同样以文本值返回，只保留自定义文本字段
+			if fv.Field.DataType != "TEXT" {
+				continue
+			}
+			value = ProjectFieldValue{Type: FieldText, Text: fv.Text}
+		case "ProjectV2ItemFieldNumberValue":
+			if fv.Number == nil {
+				continue
+			}
+			value = ProjectFieldValue{Type: FieldNumber, Number: *fv.Number}
+		case "ProjectV2ItemFieldDateValue":
+			value = ProjectFieldValue{Type: FieldDate, Text: fv.Date}
+		default:
+			continue
 		}
-
-		var selectVal struct {
-			Name string `json:"name"`
+		if name == "" {
+			continue
 		}
-		if err := json.Unmarshal(fvRaw, &selectVal); err == nil && selectVal.Name != "" {
-			pi.Status = selectVal.Name
+		if pi.Fields == nil {
+			pi.Fields = make(map[string]ProjectFieldValue)
 		}
+		pi.Fields[name] = value
 	}
 
 	return pi
@@ -0,0 +1,26 @@
+package github
+
+import "testing"
+
+func TestProjectOptionsInRepos(t *testing.T) {
+	opts := ProjectOptions{Repos: []string{"acme/web"}}
+	tests := []struct {
+		url  string
+		host string
+		want bool
+	}{
+		{"https://github.com/acme/web/issues/1", DefaultHost, true},
+		{"https://github.com/acme/web-admin/issues/1", DefaultHost, false},
+		{"https://github.com/fork/acme/web/issues/1", DefaultHost, false},
+		{"https://ghe.example.com/acme/web/issues/1", DefaultHost, false},
+		{"https://ghe.example.com/acme/web/issues/1", "ghe.example.com", true},
+	}
+	for _, tt := range tests {
+		if got := opts.inRepos(ProjectItem{URL: tt.url}, tt.host); got != tt.want {
+			t.Errorf("inRepos(%q, %q) = %v, want %v", tt.url, tt.host, got, tt.want)
+		}
+	}
+	if !(ProjectOptions{}).inRepos(ProjectItem{URL: "https://github.com/other/repo/issues/1"}, DefaultHost) {
+		t.Error("inRepos() without Repos = false, want true")
+	}
+}
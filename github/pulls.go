@@ -10,6 +10,7 @@ import (
 // ListPullRequests 获取仓库的 Pull Request 列表，按更新时间倒序排列。
 // 当遇到更新时间早于 since 的 PR 时停止获取。
 func (c *Client) ListPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]*gh.PullRequest, error) {
+	ctx = restContext(ctx)
 	opts := &gh.PullRequestListOptions{
 		State:     "all",
 		Sort:      "updated",
@@ -41,6 +42,7 @@ func (c *Client) ListPullRequests(ctx context.Context, owner, repo string, since
 
 // ListReviews 获取指定 Pull Request 的所有 Review。
 func (c *Client) ListReviews(ctx context.Context, owner, repo string, prNumber int) ([]*gh.PullRequestReview, error) {
+	ctx = restContext(ctx)
 	opts := &gh.ListOptions{PerPage: 100}
 
 	var all []*gh.PullRequestReview
@@ -60,6 +62,7 @@ func (c *Client) ListReviews(ctx context.Context, owner, repo string, prNumber i
 
 // ListReviewComments 获取仓库中自指定时间以来的所有 PR Review 评论（代码行级别评论）。
 func (c *Client) ListReviewComments(ctx context.Context, owner, repo string, since time.Time) ([]*gh.PullRequestComment, error) {
+	ctx = restContext(ctx)
 	opts := &gh.PullRequestListCommentsOptions{
 		Since: since,
 		Sort:  "updated",
@@ -80,3 +83,27 @@ func (c *Client) ListReviewComments(ctx context.Context, owner, repo string, sin
 	}
 	return all, nil
 }
+
+// ListReviewRequestEvents 获取指定 Pull Request 时间线中的 review_requested 事件，按时间先后排列。
+func (c *Client) ListReviewRequestEvents(ctx context.Context, owner, repo string, prNumber int) ([]*gh.Timeline, error) {
+	ctx = restContext(ctx)
+	opts := &gh.ListOptions{PerPage: 100}
+
+	var events []*gh.Timeline
+	for {
+		timeline, resp, err := c.REST.Issues.ListIssueTimeline(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range timeline {
+			if e.GetEvent() == "review_requested" {
+				events = append(events, e)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return events, nil
+}
@@ -0,0 +1,405 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v69/github"
+)
+
+// pullRequestsQuery 按更新时间倒序分页获取仓库的 Pull Request，
+// 同时带回作者、Assignees、合并信息、Review 请求、Review、关联的 Issue 和 head 提交的 CI 汇总状态。
+// 关联 Issue 包括将被关闭的 Issue（closingIssuesReferences）和在侧边栏手动关联的 Issue（CONNECTED_EVENT /
+// DISCONNECTED_EVENT）；只提及 PR 的 Issue（CROSS_REFERENCED_EVENT）不算关联。
+// 每个 PR 只取前 10 个 closingIssuesReferences 和前 20 条关联事件，不再翻页，超出部分被忽略。
+const pullRequestsQuery = `
+query($owner: String!, $repo: String!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequests(first: 50, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        databaseId
+        number
+        title
+        url
+        state
+        isDraft
+        createdAt
+        updatedAt
+        closedAt
+        mergedAt
+        merged
+        mergeCommit { oid }
+        headRefOid
+        author { login }
+        assignees(first: 20) { nodes { login } }
+        reviewRequests(first: 20) {
+          nodes {
+            requestedReviewer {
+              ... on User { login }
+              ... on Team { slug name }
+            }
+          }
+        }
+        reviews(first: 100) {
+          pageInfo { hasNextPage }
+          nodes {
+            databaseId
+            author { login }
+            state
+            body
+            url
+            submittedAt
+          }
+        }
+        closingIssuesReferences(first: 10) {
+          nodes { ...linkedIssue }
+        }
+        timelineItems(first: 20, itemTypes: [CONNECTED_EVENT, DISCONNECTED_EVENT]) {
+          nodes {
+            __typename
+            ... on ConnectedEvent { subject { ...linkedIssue } }
+            ... on DisconnectedEvent { subject { ...linkedIssue } }
+          }
+        }
+        commits(last: 1) {
+          nodes {
+            commit {
+              statusCheckRollup {
+                state
+                contexts(first: 100) {
+                  nodes {
+                    ... on CheckRun { name status conclusion }
+                    ... on StatusContext { context state }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+
+fragment linkedIssue on Issue {
+  number
+  title
+  url
+  state
+  repository { nameWithOwner }
+}
+`
+
+// gqlPullRequestsResponse 是 Pull Request 查询的响应结构。
+type gqlPullRequestsResponse struct {
+	Repository struct {
+		PullRequests struct {
+			PageInfo gqlPageInfo      `json:"pageInfo"`
+			Nodes    []gqlPullRequest `json:"nodes"`
+		} `json:"pullRequests"`
+	} `json:"repository"`
+}
+
+// gqlActor 是 GraphQL 中的用户（作者、Assignee 等）。
+type gqlActor struct {
+	Login string `json:"login"`
+}
+
+// gqlPullRequest 是单个 Pull Request 的 GraphQL 响应结构。
+type gqlPullRequest struct {
+	DatabaseID  int64      `json:"databaseId"`
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	State       string     `json:"state"` // OPEN、CLOSED、MERGED
+	IsDraft     bool       `json:"isDraft"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	ClosedAt    *time.Time `json:"closedAt"`
+	MergedAt    *time.Time `json:"mergedAt"`
+	Merged      bool       `json:"merged"`
+	MergeCommit *struct {
+		OID string `json:"oid"`
+	} `json:"mergeCommit"`
+	HeadRefOID string    `json:"headRefOid"`
+	Author     *gqlActor `json:"author"`
+	Assignees  struct {
+		Nodes []gqlActor `json:"nodes"`
+	} `json:"assignees"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Login string `json:"login"`
+				Slug  string `json:"slug"`
+				Name  string `json:"name"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Reviews struct {
+		PageInfo gqlPageInfo `json:"pageInfo"`
+		Nodes    []gqlReview `json:"nodes"`
+	} `json:"reviews"`
+	ClosingIssuesReferences struct {
+		Nodes []gqlLinkedIssue `json:"nodes"`
+	} `json:"closingIssuesReferences"`
+	TimelineItems struct {
+		Nodes []struct {
+			Typename string         `json:"__typename"` // ConnectedEvent、DisconnectedEvent
+			Subject  gqlLinkedIssue `json:"subject"`
+		} `json:"nodes"`
+	} `json:"timelineItems"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *gqlStatusRollup `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// gqlLinkedIssue 是与 PR 关联的 Issue。关联对象是 Pull Request 时各字段为零值。
+type gqlLinkedIssue struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	State      string `json:"state"` // OPEN、CLOSED
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+// gqlStatusRollup 是提交的 CI 汇总状态（statusCheckRollup）。
+type gqlStatusRollup struct {
+	State    string `json:"state"` // SUCCESS、FAILURE、ERROR、PENDING、EXPECTED
+	Contexts struct {
+		Nodes []struct {
+			Name       string `json:"name"`       // CheckRun
+			Status     string `json:"status"`     // CheckRun
+			Conclusion string `json:"conclusion"` // CheckRun
+			Context    string `json:"context"`    // StatusContext
+			State      string `json:"state"`      // StatusContext
+		} `json:"nodes"`
+	} `json:"contexts"`
+}
+
+// gqlReview 是单个 Review 的 GraphQL 响应结构。
+type gqlReview struct {
+	DatabaseID  int64      `json:"databaseId"`
+	Author      *gqlActor  `json:"author"`
+	State       string     `json:"state"`
+	Body        string     `json:"body"`
+	URL         string     `json:"url"`
+	SubmittedAt *time.Time `json:"submittedAt"`
+}
+
+// LinkedIssue 表示与 PR 关联的同仓库 Issue。
+type LinkedIssue struct {
+	Number int
+	Title  string
+	URL    string
+	State  string // open、closed
+}
+
+// PullRequestDetails 是 ListPullRequestsWithReviews 的结果，map 均以 PR 编号为键。
+type PullRequestDetails struct {
+	PullRequests []*gh.PullRequest
+	Reviews      map[int][]*gh.PullRequestReview
+	CI           map[int]CIStatus      // head 提交的 CI 状态，没有任何检查的 PR 不出现
+	LinkedIssues map[int][]LinkedIssue // 关联的同仓库 Issue（closingIssuesReferences 和时间线中的关联、引用事件）
+}
+
+// ListPullRequestsWithReviews 通过 GraphQL 分页获取仓库的 Pull Request 及其 Review、CI 状态和关联 Issue，
+// 按更新时间倒序排列。当遇到更新时间早于 since 的 PR 时停止获取。
+//
+// 与 ListPullRequests + ListReviews + GetCIStatus 相比，每页 50 个 PR 只需一次请求。
+// 单个 PR 的 Review 超过 100 条时，回退到 REST 接口获取该 PR 的全部 Review。
+func (c *Client) ListPullRequestsWithReviews(ctx context.Context, owner, repo string, since time.Time) (*PullRequestDetails, error) {
+	details := &PullRequestDetails{
+		Reviews:      make(map[int][]*gh.PullRequestReview),
+		CI:           make(map[int]CIStatus),
+		LinkedIssues: make(map[int][]LinkedIssue),
+	}
+
+	var cursor *string
+	for {
+		vars := map[string]any{"owner": owner, "repo": repo}
+		if cursor != nil {
+			vars["cursor"] = *cursor
+		}
+
+		var resp gqlPullRequestsResponse
+		if err := c.GraphQL(ctx, pullRequestsQuery, vars, &resp); err != nil {
+			return nil, fmt.Errorf("fetching pull requests for %s/%s: %w", owner, repo, err)
+		}
+
+		for _, node := range resp.Repository.PullRequests.Nodes {
+			if node.UpdatedAt.Before(since) {
+				return details, nil
+			}
+			details.PullRequests = append(details.PullRequests, node.toPullRequest())
+			if status := node.ciStatus(); status.State != "" {
+				details.CI[node.Number] = status
+			}
+			if linked := node.linkedIssues(owner + "/" + repo); len(linked) > 0 {
+				details.LinkedIssues[node.Number] = linked
+			}
+
+			if node.Reviews.PageInfo.HasNextPage {
+				all, err := c.ListReviews(ctx, owner, repo, node.Number)
+				if err != nil {
+					return nil, err
+				}
+				details.Reviews[node.Number] = all
+				continue
+			}
+			for _, r := range node.Reviews.Nodes {
+				details.Reviews[node.Number] = append(details.Reviews[node.Number], r.toPullRequestReview())
+			}
+		}
+
+		if !resp.Repository.PullRequests.PageInfo.HasNextPage {
+			break
+		}
+		next := resp.Repository.PullRequests.PageInfo.EndCursor
+		cursor = &next
+	}
+	return details, nil
+}
+
+// ciStatus 返回 head 提交的 CI 汇总状态。汇总状态取自 statusCheckRollup，失败的检查名称取自前 100 个检查。
+func (p gqlPullRequest) ciStatus() CIStatus {
+	if len(p.Commits.Nodes) == 0 || p.Commits.Nodes[0].Commit.StatusCheckRollup == nil {
+		return CIStatus{}
+	}
+	rollup := p.Commits.Nodes[0].Commit.StatusCheckRollup
+
+	var checks ciRollup
+	for _, ctx := range rollup.Contexts.Nodes {
+		if ctx.Context != "" {
+			checks.add(ctx.Context, statusResult(ctx.State))
+		} else {
+			checks.add(ctx.Name, checkRunResult(ctx.Status, ctx.Conclusion))
+		}
+	}
+	status := CIStatus{State: statusResult(rollup.State)}
+	if status.State == CIFailure {
+		status.Failing = checks.failing
+	}
+	return status
+}
+
+// linkedIssues 返回与 PR 关联的同仓库 Issue，按编号去重：先取将被关闭的 Issue，再按时间顺序应用时间线事件，
+// 手动关联（ConnectedEvent）加入，取消关联（DisconnectedEvent）移除。
+// 将被关闭的 Issue 不会因取消关联而移除。
+func (p gqlPullRequest) linkedIssues(fullRepo string) []LinkedIssue {
+	var linked []LinkedIssue
+	closing := make(map[int]bool)
+	add := func(ref gqlLinkedIssue) {
+		// 只关联同仓库的 Issue
+		if ref.Number == 0 || !strings.EqualFold(ref.Repository.NameWithOwner, fullRepo) {
+			return
+		}
+		for _, li := range linked {
+			if li.Number == ref.Number {
+				return
+			}
+		}
+		linked = append(linked, LinkedIssue{
+			Number: ref.Number,
+			Title:  ref.Title,
+			URL:    ref.URL,
+			State:  strings.ToLower(ref.State),
+		})
+	}
+
+	for _, ref := range p.ClosingIssuesReferences.Nodes {
+		add(ref)
+		closing[ref.Number] = true
+	}
+	for _, item := range p.TimelineItems.Nodes {
+		switch item.Typename {
+		case "ConnectedEvent":
+			add(item.Subject)
+		case "DisconnectedEvent":
+			if closing[item.Subject.Number] {
+				continue
+			}
+			for i, li := range linked {
+				if li.Number == item.Subject.Number {
+					linked = append(linked[:i], linked[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return linked
+}
+
+// toPullRequest 将 GraphQL 响应转换为 go-github 的 PullRequest，与 REST 接口返回的字段语义一致。
+func (p gqlPullRequest) toPullRequest() *gh.PullRequest {
+	pr := &gh.PullRequest{
+		ID:        gh.Ptr(p.DatabaseID),
+		Number:    gh.Ptr(p.Number),
+		Title:     gh.Ptr(p.Title),
+		HTMLURL:   gh.Ptr(p.URL),
+		State:     gh.Ptr(restState(p.State)),
+		Draft:     gh.Ptr(p.IsDraft),
+		Merged:    gh.Ptr(p.Merged),
+		CreatedAt: &gh.Timestamp{Time: p.CreatedAt},
+		UpdatedAt: &gh.Timestamp{Time: p.UpdatedAt},
+		Head:      &gh.PullRequestBranch{SHA: gh.Ptr(p.HeadRefOID)},
+	}
+	if p.ClosedAt != nil {
+		pr.ClosedAt = &gh.Timestamp{Time: *p.ClosedAt}
+	}
+	if p.MergedAt != nil {
+		pr.MergedAt = &gh.Timestamp{Time: *p.MergedAt}
+	}
+	if p.MergeCommit != nil {
+		pr.MergeCommitSHA = gh.Ptr(p.MergeCommit.OID)
+	}
+	if p.Author != nil {
+		pr.User = &gh.User{Login: gh.Ptr(p.Author.Login)}
+	}
+	for _, a := range p.Assignees.Nodes {
+		pr.Assignees = append(pr.Assignees, &gh.User{Login: gh.Ptr(a.Login)})
+	}
+	for _, rr := range p.ReviewRequests.Nodes {
+		reviewer := rr.RequestedReviewer
+		switch {
+		case reviewer.Login != "":
+			pr.RequestedReviewers = append(pr.RequestedReviewers, &gh.User{Login: gh.Ptr(reviewer.Login)})
+		case reviewer.Slug != "":
+			pr.RequestedTeams = append(pr.RequestedTeams, &gh.Team{Slug: gh.Ptr(reviewer.Slug), Name: gh.Ptr(reviewer.Name)})
+		}
+	}
+	return pr
+}
+
+// toPullRequestReview 将 GraphQL 响应转换为 go-github 的 PullRequestReview。
+func (r gqlReview) toPullRequestReview() *gh.PullRequestReview {
+	review := &gh.PullRequestReview{
+		ID:      gh.Ptr(r.DatabaseID),
+		State:   gh.Ptr(r.State),
+		Body:    gh.Ptr(r.Body),
+		HTMLURL: gh.Ptr(r.URL),
+	}
+	if r.Author != nil {
+		review.User = &gh.User{Login: gh.Ptr(r.Author.Login)}
+	}
+	if r.SubmittedAt != nil {
+		review.SubmittedAt = &gh.Timestamp{Time: *r.SubmittedAt}
+	}
+	return review
+}
+
+// restState 将 GraphQL 的 PR 状态（OPEN、CLOSED、MERGED）转换为 REST 接口的 open / closed。
+func restState(state string) string {
+	if strings.EqualFold(state, "OPEN") {
+		return "open"
+	}
+	return "closed"
+}
@@ -0,0 +1,219 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gh "github.com/google/go-github/v69/github"
+)
+
+const (
+	// DefaultConcurrency 是默认的最大并发请求数。
+	DefaultConcurrency = 8
+	// maxRetries 是触发限流后的最大重试次数。
+	maxRetries = 5
+	// maxBackoff 是没有 Retry-After 和重置时间可参考时的最大退避时间。
+	maxBackoff = 2 * time.Minute
+)
+
+// RateLimit 表示 GitHub API 的配额状态。
+type RateLimit struct {
+	Limit     int       // 配额上限
+	Remaining int       // 剩余配额
+	Reset     time.Time // 配额重置时间
+	RetryAt   time.Time // 触发限流后等待重试的时间，未在等待时为零值
+}
+
+// rateLimitTransport 限制同时进行的请求数，并在触发主/次级限流时等待后重试。
+//
+// 处理的限流响应：
+//   - 429 或带 Retry-After 头的 403：按 Retry-After 等待
+//   - X-RateLimit-Remaining 为 0 的 403/429：等待至 X-RateLimit-Reset
+//   - 响应体提示 secondary rate limit 的 403：指数退避
+type rateLimitTransport struct {
+	next     http.RoundTripper
+	sem      chan struct{}
+	observer func(RateLimit)
+
+	mu        sync.Mutex
+	pauseTill time.Time // 配额耗尽时，所有请求在此时间前暂停发送
+	last      RateLimit // 最近一次收到的配额状态
+}
+
+// newRateLimitTransport 创建最多 concurrency 个并发请求的 rateLimitTransport。
+func newRateLimitTransport(next http.RoundTripper, concurrency int, observer func(RateLimit)) *rateLimitTransport {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	return &rateLimitTransport{
+		next:     next,
+		sem:      make(chan struct{}, concurrency),
+		observer: observer,
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper。
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := t.waitPause(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.do(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		rate, hasRate := parseRateLimit(resp.Header)
+		if hasRate {
+			t.mu.Lock()
+			t.last = rate
+			t.mu.Unlock()
+			t.notify(rate)
+		}
+
+		wait, limited := t.retryDelay(resp, rate, hasRate, attempt)
+		if !limited || attempt >= maxRetries {
+			return resp, nil
+		}
+
+		// 重试前需要能重放请求体
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+		resp.Body.Close()
+
+		retryAt := time.Now().Add(wait)
+		t.pause(retryAt)
+		t.mu.Lock()
+		waiting := t.last
+		t.mu.Unlock()
+		waiting.RetryAt = retryAt
+		t.notify(waiting)
+	}
+}
+
+// do 在并发槽位内发送一次请求。
+func (t *rateLimitTransport) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-t.sem }()
+	return t.next.RoundTrip(req)
+}
+
+// retryDelay 判断响应是否为限流响应，并计算重试前需要等待的时间。
+func (t *rateLimitTransport) retryDelay(resp *http.Response, rate RateLimit, hasRate bool, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+
+	if hasRate && rate.Remaining == 0 && !rate.Reset.IsZero() {
+		// 多等一秒，避免服务端时钟误差
+		return time.Until(rate.Reset) + time.Second, true
+	}
+
+	if resp.StatusCode == http.StatusForbidden && !isSecondaryRateLimit(resp) {
+		return 0, false
+	}
+
+	backoff := time.Duration(math.Pow(2, float64(attempt))) * 5 * time.Second
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff, true
+}
+
+// isSecondaryRateLimit 检查 403 响应体是否为次级限流（abuse detection）提示。
+// 读取后的响应体会被还原，调用方仍可正常读取。
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse")
+}
+
+// waitPause 在配额耗尽期间阻塞，直到暂停结束或 ctx 取消。
+func (t *rateLimitTransport) waitPause(ctx context.Context) error {
+	t.mu.Lock()
+	till := t.pauseTill
+	t.mu.Unlock()
+
+	d := time.Until(till)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause 让所有请求暂停到 till，已有更晚的暂停时间时保持不变。
+func (t *rateLimitTransport) pause(till time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if till.After(t.pauseTill) {
+		t.pauseTill = till
+	}
+}
+
+// notify 将配额状态通知给观察者。
+func (t *rateLimitTransport) notify(rate RateLimit) {
+	if t.observer != nil {
+		t.observer(rate)
+	}
+}
+
+// parseRateLimit 从响应头解析配额状态，响应中没有配额信息时返回 false。
+func parseRateLimit(h http.Header) (RateLimit, bool) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	rate := RateLimit{Remaining: remaining}
+	rate.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rate.Reset = time.Unix(reset, 0)
+	}
+	return rate, true
+}
+
+// restContext 跳过 go-github 内置的配额预检查。
+// 配额耗尽时由 rateLimitTransport 等待重置后重试，而不是由 go-github 直接返回错误。
+func restContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, gh.BypassRateLimitCheck, true)
+}
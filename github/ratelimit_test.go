@@ -0,0 +1,219 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// limitResponse 构造一个限流测试用的 HTTP 响应。
+func limitResponse(status int, header map[string]string, body string) *http.Response {
+	h := http.Header{}
+	for k, v := range header {
+		h.Set(k, v)
+	}
+	return &http.Response{StatusCode: status, Header: h, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestRetryDelay(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name        string
+		resp        *http.Response
+		attempt     int
+		wantLimited bool
+		wantMin     time.Duration
+		wantMax     time.Duration
+	}{
+		{
+			name: "ok",
+			resp: limitResponse(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "0"}, ""),
+		},
+		{
+			name:        "429 retry-after",
+			resp:        limitResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}, ""),
+			wantLimited: true,
+			wantMin:     30 * time.Second,
+			wantMax:     30 * time.Second,
+		},
+		{
+			name:        "403 retry-after",
+			resp:        limitResponse(http.StatusForbidden, map[string]string{"Retry-After": "7"}, ""),
+			wantLimited: true,
+			wantMin:     7 * time.Second,
+			wantMax:     7 * time.Second,
+		},
+		{
+			// Retry-After 优先于重置时间
+			name: "retry-after over reset",
+			resp: limitResponse(http.StatusForbidden, map[string]string{
+				"Retry-After": "3", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset,
+			}, ""),
+			wantLimited: true,
+			wantMin:     3 * time.Second,
+			wantMax:     3 * time.Second,
+		},
+		{
+			// 等待至重置时间后再多等一秒
+			name: "primary limit reset",
+			resp: limitResponse(http.StatusForbidden, map[string]string{
+				"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset,
+			}, `{"message":"API rate limit exceeded"}`),
+			wantLimited: true,
+			wantMin:     55 * time.Second,
+			wantMax:     61 * time.Second,
+		},
+		{
+			name: "invalid retry-after falls back to reset",
+			resp: limitResponse(http.StatusTooManyRequests, map[string]string{
+				"Retry-After": "soon", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset,
+			}, ""),
+			wantLimited: true,
+			wantMin:     55 * time.Second,
+			wantMax:     61 * time.Second,
+		},
+		{
+			name:        "secondary limit",
+			resp:        limitResponse(http.StatusForbidden, nil, `{"message":"You have exceeded a secondary rate limit."}`),
+			wantLimited: true,
+			wantMin:     5 * time.Second,
+			wantMax:     5 * time.Second,
+		},
+		{
+			name:        "abuse detection backoff",
+			resp:        limitResponse(http.StatusForbidden, nil, `{"message":"abuse detection mechanism"}`),
+			attempt:     2,
+			wantLimited: true,
+			wantMin:     20 * time.Second,
+			wantMax:     20 * time.Second,
+		},
+		{
+			name:        "backoff capped",
+			resp:        limitResponse(http.StatusForbidden, nil, `{"message":"secondary rate limit"}`),
+			attempt:     10,
+			wantLimited: true,
+			wantMin:     maxBackoff,
+			wantMax:     maxBackoff,
+		},
+		{
+			name:        "429 without hints",
+			resp:        limitResponse(http.StatusTooManyRequests, nil, ""),
+			attempt:     1,
+			wantLimited: true,
+			wantMin:     10 * time.Second,
+			wantMax:     10 * time.Second,
+		},
+		{
+			// 权限不足等普通 403 不重试
+			name: "forbidden",
+			resp: limitResponse(http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "4000"}, `{"message":"Resource not accessible by integration"}`),
+		},
+	}
+
+	tr := newRateLimitTransport(http.DefaultTransport, 1, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, hasRate := parseRateLimit(tt.resp.Header)
+			got, limited := tr.retryDelay(tt.resp, rate, hasRate, tt.attempt)
+			if limited != tt.wantLimited {
+				t.Fatalf("retryDelay() limited = %v, want %v", limited, tt.wantLimited)
+			}
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("retryDelay() = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestIsSecondaryRateLimitKeepsBody(t *testing.T) {
+	const body = `{"message":"You have exceeded a secondary rate limit"}`
+	resp := limitResponse(http.StatusForbidden, nil, body)
+	if !isSecondaryRateLimit(resp) {
+		t.Fatal("isSecondaryRateLimit() = false, want true")
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != body {
+		t.Errorf("body after check = %q, want %q", got, body)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	h := http.Header{}
+	if _, ok := parseRateLimit(h); ok {
+		t.Error("parseRateLimit() without headers ok = true")
+	}
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", "42")
+	h.Set("X-RateLimit-Reset", "1790000000")
+	rate, ok := parseRateLimit(h)
+	if !ok || rate.Limit != 5000 || rate.Remaining != 42 || !rate.Reset.Equal(time.Unix(1790000000, 0)) {
+		t.Errorf("parseRateLimit() = %+v, %v", rate, ok)
+	}
+}
+
+func TestRateLimitTransportRetries(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("request %d body = %q, want replayed payload", requests.Load()+1, body)
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var observed []RateLimit
+	client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, 2, func(r RateLimit) {
+		observed = append(observed, r)
+	})}
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || requests.Load() != 2 {
+		t.Fatalf("status = %d after %d requests, want 200 after 2", resp.StatusCode, requests.Load())
+	}
+	// 限流响应、等待重试、成功响应各通知一次
+	if len(observed) != 3 {
+		t.Fatalf("observer calls = %d, want 3: %+v", len(observed), observed)
+	}
+	if observed[0].Remaining != 0 || observed[1].RetryAt.IsZero() || observed[2].Remaining != 4999 {
+		t.Errorf("observed = %+v", observed)
+	}
+}
+
+func TestRateLimitTransportGivesUp(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, 1, nil)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || requests.Load() != maxRetries+1 {
+		t.Errorf("status = %d after %d requests, want 429 after %d", resp.StatusCode, requests.Load(), maxRetries+1)
+	}
+}
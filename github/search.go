@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v69/github"
+)
+
+// searchQualifiers 是发现用户活动时使用的搜索限定符。
+// involves 覆盖作者、评论者、Assignee 和被提及者，reviewed-by 补充只提交过 Review 的 PR。
+var searchQualifiers = []string{"involves", "reviewed-by"}
+
+// SearchActiveRepos 通过 Search API 查找用户在 [since, until] 内于组织 org 下有活动的仓库，
+// 返回按名称排序的 "owner/repo" 列表。
+//
+// 对每个限定符执行一次 Issue/PR 搜索（如 "org:acme involves:alice updated:2026-10-01..2026-10-16"），
+// 从结果的 repository_url 中提取仓库并去重。Search API 每个查询最多返回 1000 条结果。
+func (c *Client) SearchActiveRepos(ctx context.Context, org, user string, since, until time.Time) ([]string, error) {
+	ctx = restContext(ctx)
+	updated := fmt.Sprintf("updated:%s..%s", since.Format("2006-01-02"), until.Format("2006-01-02"))
+
+	seen := make(map[string]bool)
+	for _, qualifier := range searchQualifiers {
+		query := fmt.Sprintf("org:%s %s:%s %s", org, qualifier, user, updated)
+		opts := &gh.SearchOptions{
+			Sort:        "updated",
+			ListOptions: gh.ListOptions{PerPage: 100},
+		}
+		for {
+			result, resp, err := c.REST.Search.Issues(ctx, query, opts)
+			if err != nil {
+				return nil, fmt.Errorf("searching %q: %w", query, err)
+			}
+			for _, issue := range result.Issues {
+				if repo := RepoFromURL(issue.GetRepositoryURL()); repo != "" {
+					seen[repo] = true
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+
+	repos := make([]string, 0, len(seen))
+	for repo := range seen {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
+
+// SearchReviewRequests 通过 Search API 查找正在等待用户 Review 的 open PR，包括通过团队请求的。
+// 用户提交 Review 后请求即被移除，因此结果只包含尚未处理的请求。
+func (c *Client) SearchReviewRequests(ctx context.Context, user string) ([]*gh.Issue, error) {
+	ctx = restContext(ctx)
+	query := fmt.Sprintf("is:pr is:open archived:false review-requested:%s", user)
+	opts := &gh.SearchOptions{
+		Sort:        "created",
+		Order:       "asc",
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+
+	var all []*gh.Issue
+	for {
+		result, resp, err := c.REST.Search.Issues(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("searching %q: %w", query, err)
+		}
+		all = append(all, result.Issues...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// RepoFromURL 从 API 仓库地址（如 https://api.github.com/repos/owner/repo）中提取 "owner/repo"。
+func RepoFromURL(u string) string {
+	_, path, ok := strings.Cut(u, "/repos/")
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// URLInRepo 判断网页地址（如 Issue、PR 或项目工作项的链接）是否属于仓库 fullRepo。
+// fullRepo 的格式为 "owner/repo"（GitHub.com 上的仓库）或 "host/owner/repo"。
+// 按 "<host>/<owner>/<repo>/" 前缀匹配（不区分大小写），避免 acme/web 误匹配 acme/web-admin 或其他主机上的同名仓库。
+func URLInRepo(u, fullRepo string) bool {
+	if strings.Count(fullRepo, "/") == 1 {
+		fullRepo = DefaultHost + "/" + fullRepo
+	}
+	_, rest, ok := strings.Cut(u, "://")
+	return ok && strings.HasPrefix(strings.ToLower(rest), strings.ToLower(fullRepo)+"/")
+}
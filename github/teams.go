@@ -0,0 +1,32 @@
+package github
+
+import (
+	"context"
+
+	gh "github.com/google/go-github/v69/github"
+)
+
+// ListTeamMembers 获取组织团队的全部成员 login（包含子团队成员）。
+// slug 为团队的 URL 标识，如 "backend-team"。需要 Token 具有 read:org 权限。
+func (c *Client) ListTeamMembers(ctx context.Context, org, slug string) ([]string, error) {
+	ctx = restContext(ctx)
+	opts := &gh.TeamListTeamMembersOptions{
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+
+	var all []string
+	for {
+		users, resp, err := c.REST.Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			all = append(all, u.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
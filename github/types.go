@@ -1,7 +1,10 @@
 // Package github 提供 GitHub REST 和 GraphQL API 客户端。
 package github
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // ProjectIteration 表示 GitHub Projects v2 迭代字段中的单个迭代。
 type ProjectIteration struct {
@@ -13,14 +16,42 @@ type ProjectIteration struct {
 
 // ProjectItem 表示 GitHub Projects v2 项目中的一个工作项。
 type ProjectItem struct {
-	Title     string   // 标题
-	Number    int      // Issue 或 PR 编号
-	URL       string   // 链接地址
-	State     string   // 状态（OPEN、CLOSED、MERGED）
-	Type      string   // 类型（"Issue" 或 "PullRequest"）
-	Iteration string   // 所属迭代标题
-	Status    string   // 状态字段值（如 "Done"、"In Progress"）
-	Assignees []string // 负责人列表（GitHub login）
+	Title     string    // 标题
+	Number    int       // Issue 或 PR 编号
+	URL       string    // 链接地址
+	State     string    // 状态（OPEN、CLOSED、MERGED）
+	ClosedAt  time.Time // 关闭时间（PR 优先取合并时间），未关闭时为零值
+	Type      string    // 类型（"Issue" 或 "PullRequest"）
+	Iteration string    // 所属迭代标题
+	Status    string    // 状态字段值（如 "Done"、"In Progress"）
+	Assignees []string  // 负责人列表（GitHub login）
+
+	// Fields 迭代和状态以外的自定义字段值（如优先级、估算），以字段名为键
+	Fields map[string]ProjectFieldValue
+}
+
+// 项目自定义字段的类型。
+const (
+	FieldText         = "text"
+	FieldNumber       = "number"
+	FieldDate         = "date"
+	FieldSingleSelect = "single_select"
+	FieldIteration    = "iteration"
+)
+
+// ProjectFieldValue 表示工作项的一个自定义字段值。
+type ProjectFieldValue struct {
+	Type   string  // 字段类型：FieldText、FieldNumber、FieldDate、FieldSingleSelect 或 FieldIteration
+	Text   string  // 文本值：文本内容、单选项名称、日期（2006-01-02）或迭代标题
+	Number float64 // 数字字段的值
+}
+
+// String 返回字段值的文本形式，数字字段去掉多余的小数位。
+func (v ProjectFieldValue) String() string {
+	if v.Type == FieldNumber {
+		return strconv.FormatFloat(v.Number, 'f', -1, 64)
+	}
+	return v.Text
 }
 
 // Project 表示一个 GitHub Projects v2 项目，包含迭代信息。
@@ -44,8 +75,9 @@ const (
 )
 
 // ClassifyIteration 根据给定的参考时间，判断迭代属于过去、当前还是未来。
+// 迭代起止日期按 now 所在时区解释。
 func ClassifyIteration(iter ProjectIteration, now time.Time) IterationCategory {
-	start, err := time.Parse("2006-01-02", iter.StartDate)
+	start, err := time.ParseInLocation("2006-01-02", iter.StartDate, now.Location())
 	if err != nil {
 		return IterationPrevious
 	}
@@ -68,15 +100,27 @@ type RelevantIterations struct {
 	Next     *ProjectIteration // 最近的未来迭代（可能为 nil）
 }
 
+// Titles 返回三个迭代中存在的迭代标题。
+func (r RelevantIterations) Titles() []string {
+	var titles []string
+	for _, iter := range []*ProjectIteration{r.Previous, r.Current, r.Next} {
+		if iter != nil {
+			titles = append(titles, iter.Title)
+		}
+	}
+	return titles
+}
+
 // FindRelevantIterations 从迭代列表中找出最相关的三个迭代：
 // 最近结束的一个 previous、当前进行中的 current、最近将开始的 next。
+// 迭代起止日期按 now 所在时区解释。
 func FindRelevantIterations(iterations []ProjectIteration, now time.Time) RelevantIterations {
 	var result RelevantIterations
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	for i := range iterations {
 		iter := &iterations[i]
-		start, err := time.Parse("2006-01-02", iter.StartDate)
+		start, err := time.ParseInLocation("2006-01-02", iter.StartDate, now.Location())
 		if err != nil {
 			continue
 		}
@@ -88,7 +132,7 @@ func FindRelevantIterations(iterations []ProjectIteration, now time.Time) Releva
 			if result.Next == nil {
 				result.Next = iter
 			} else {
-				nextStart, _ := time.Parse("2006-01-02", result.Next.StartDate)
+				nextStart, _ := time.ParseInLocation("2006-01-02", result.Next.StartDate, now.Location())
 				if start.Before(nextStart) {
 					result.Next = iter
 				}
@@ -98,7 +142,7 @@ func FindRelevantIterations(iterations []ProjectIteration, now time.Time) Releva
 			if result.Previous == nil {
 				result.Previous = iter
 			} else {
-				prevStart, _ := time.Parse("2006-01-02", result.Previous.StartDate)
+				prevStart, _ := time.ParseInLocation("2006-01-02", result.Previous.StartDate, now.Location())
 				prevEnd := prevStart.AddDate(0, 0, result.Previous.Duration)
 				if end.After(prevEnd) {
 					result.Previous = iter
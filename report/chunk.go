@@ -0,0 +1,331 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxPromptTokens 是单个 Prompt 的默认 token 上限（估算值），超出时分段总结后再合并。
+// 取值低于常见模型的上下文窗口，为输出和估算误差留出余量。
+const DefaultMaxPromptTokens = 60000
+
+// EstimateTokens 估算文本的 token 数：ASCII 字符约 4 个一个 token，其他字符（如中文）按每个一个 token 计。
+// 估算偏保守，只用于判断是否需要分段，不代表模型实际计费的 token 数。
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		i += size
+	}
+	return (ascii+3)/4 + other
+}
+
+// SummaryPlan 表示 AI 报告的生成计划。
+// Chunks 为空时直接使用 Prompt 生成报告；否则先逐段总结 Chunks（map），再将各段结果交给
+// Merge 得到下一步的计划（reduce），直到 Chunks 为空。
+type SummaryPlan struct {
+	Prompt string         // 完整 Prompt，未分段时使用
+	Chunks []SummaryChunk // 分段总结的 Prompt，未超出上限时为空
+
+	maxTokens int
+	final     func(parts []summaryPart) string            // 构建合并为最终报告的 Prompt
+	regroup   func(label string, index, total int) string // 构建中间合并阶段的 Prompt 指令模板
+}
+
+// SummaryChunk 表示一段工作数据的总结 Prompt。
+type SummaryChunk struct {
+	Label  string // 分段说明（月份或仓库），如 "2026-09" 或 "own/repo1, own/repo2"
+	Prompt string
+}
+
+// summaryPart 表示一段已整理好的总结结果。
+type summaryPart struct {
+	label string
+	text  string
+}
+
+// Merge 根据各段总结结果（与 Chunks 一一对应）构建下一步的计划。合并 Prompt 的估算 token 数不超过上限
+// （或只剩一段结果）时，返回以合并 Prompt 生成最终报告的计划；否则将各段结果按顺序分组，每组至少两段，
+// 返回逐组合并的计划，调用方总结新的 Chunks 后再次调用 Merge。每轮的分段数严格减少，合并过程必然结束。
+func (p SummaryPlan) Merge(partials []string) SummaryPlan {
+	if p.final == nil {
+		return SummaryPlan{Prompt: p.Prompt}
+	}
+	parts := make([]summaryPart, len(p.Chunks))
+	for i, c := range p.Chunks {
+		parts[i].label = c.Label
+		if i < len(partials) {
+			parts[i].text = strings.TrimSpace(partials[i])
+		}
+	}
+
+	next := SummaryPlan{Prompt: p.final(parts), maxTokens: p.maxTokens, final: p.final, regroup: p.regroup}
+	if EstimateTokens(next.Prompt) <= p.maxTokens || len(parts) < 2 {
+		next.final, next.regroup = nil, nil
+		return next
+	}
+
+	groups := packSummaryParts(parts, p.maxTokens-EstimateTokens(p.regroup("", 0, 0)))
+	next.Chunks = make([]SummaryChunk, len(groups))
+	for i, g := range groups {
+		labels := make([]string, len(g))
+		for j, part := range g {
+			labels[j] = part.label
+		}
+		label := strings.Join(labels, ", ")
+
+		var sb strings.Builder
+		sb.WriteString(p.regroup(label, i+1, len(groups)))
+		writeSummaryParts(&sb, g)
+		next.Chunks[i] = SummaryChunk{Label: label, Prompt: sb.String()}
+	}
+	return next
+}
+
+// packSummaryParts 将各段结果按顺序装入不超过 budget（估算 token 数）的分组。为保证每轮合并后分段数减少，
+// 每组至少包含两段（超出 budget 也不拆开），只有最后一组可能只有一段。
+func packSummaryParts(parts []summaryPart, budget int) [][]summaryPart {
+	var groups [][]summaryPart
+	var cur []summaryPart
+	curTokens := 0
+	for _, part := range parts {
+		t := EstimateTokens(part.text)
+		if len(cur) >= 2 && curTokens+t > budget {
+			groups = append(groups, cur)
+			cur, curTokens = nil, 0
+		}
+		cur = append(cur, part)
+		curTokens += t
+	}
+	if len(cur) > 0 {
+		groups = append(groups, cur)
+	}
+	return groups
+}
+
+// writeSummaryParts 逐段写入总结结果，每段以 "--- <分段说明> ---" 开头。
+func writeSummaryParts(sb *strings.Builder, parts []summaryPart) {
+	for _, part := range parts {
+		fmt.Fprintf(sb, "\n--- %s ---\n", part.label)
+		if part.text != "" {
+			sb.WriteString(part.text)
+			sb.WriteString("\n")
+		}
+	}
+}
+
+// PlanSummary 构建 AI 报告的生成计划。完整 Prompt 的估算 token 数不超过 maxTokens（<= 0 时使用
+// DefaultMaxPromptTokens）时不分段；否则将工作条目分段：时间范围跨月时按月份，否则按仓库，
+// 相邻的小分段合并到同一段，单个分段超出上限时按条目继续拆分。计划条目不分段，在合并时一并提供；
+// 合并 Prompt 仍超出上限时，由 SummaryPlan.Merge 分组逐轮合并。
+func PlanSummary(reports []RepoReport, since, until time.Time, user string, rt ReportType, maxTokens int) SummaryPlan {
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxPromptTokens
+	}
+	workItems, planItems := extractItems(reports, user, since, until, rt)
+	plan := SummaryPlan{Prompt: buildSummaryPromptFromItems(workItems, planItems, since, until, user, rt)}
+	if EstimateTokens(plan.Prompt) <= maxTokens || len(workItems) < 2 {
+		return plan
+	}
+
+	byMonth := since.Format("2006-01") != until.Format("2006-01")
+	groups := groupWorkItems(workItems, byMonth)
+	budget := maxTokens - EstimateTokens(buildChunkPromptTemplate(since, until, user, rt, "", 0, 0))
+	chunks := packWorkGroups(groups, rt, budget)
+
+	plan.Chunks = make([]SummaryChunk, len(chunks))
+	for i, c := range chunks {
+		var sb strings.Builder
+		sb.WriteString(buildChunkPromptTemplate(since, until, user, rt, c.label, i+1, len(chunks)))
+		sb.WriteString(formatWorkData(c.items, rt))
+		plan.Chunks[i] = SummaryChunk{Label: c.label, Prompt: sb.String()}
+	}
+	groupBy := "仓库"
+	if byMonth {
+		groupBy = "月份"
+	}
+	plan.maxTokens = maxTokens
+	plan.final = func(parts []summaryPart) string {
+		return buildMergePrompt(parts, planItems, since, until, user, rt, groupBy)
+	}
+	plan.regroup = func(label string, index, total int) string {
+		return buildRegroupPromptTemplate(since, until, user, rt, false, label, index, total)
+	}
+	return plan
+}
+
+// workGroup 表示一组工作条目及其分段说明。
+type workGroup struct {
+	label string
+	items []WorkItem
+}
+
+// groupWorkItems 将工作条目按月份（byMonth 为 true）或仓库分组。
+// 按月份分组时按时间排序，没有日期的条目排在最后；按仓库分组时保持条目中的仓库顺序。
+func groupWorkItems(items []WorkItem, byMonth bool) []workGroup {
+	var keys []string
+	grouped := make(map[string][]WorkItem)
+	for _, item := range items {
+		key := item.Repo
+		if byMonth {
+			key = ""
+			if len(item.Date) >= len("2006-01") {
+				key = item.Date[:len("2006-01")]
+			}
+		}
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], item)
+	}
+	if byMonth {
+		sort.SliceStable(keys, func(i, j int) bool {
+			if keys[i] == "" || keys[j] == "" {
+				return keys[j] == ""
+			}
+			return keys[i] < keys[j]
+		})
+	}
+
+	groups := make([]workGroup, len(keys))
+	for i, key := range keys {
+		label := key
+		if label == "" {
+			label = "日期未知"
+		}
+		groups[i] = workGroup{label: label, items: grouped[key]}
+	}
+	return groups
+}
+
+// packWorkGroups 将分组依次装入不超过 budget（估算 token 数）的分段：相邻分组能放下时合并，
+// 单个分组超出 budget 时按条目拆分为多段（如 "2026-09（第 2 段）"）。每段至少包含一个条目。
+func packWorkGroups(groups []workGroup, rt ReportType, budget int) []workGroup {
+	var chunks []workGroup
+	var cur workGroup
+	var labels []string
+	curTokens := 0
+	flush := func() {
+		if len(cur.items) > 0 {
+			cur.label = strings.Join(labels, ", ")
+			chunks = append(chunks, cur)
+		}
+		cur, labels, curTokens = workGroup{}, nil, 0
+	}
+
+	for _, g := range groups {
+		tokens := EstimateTokens(formatWorkData(g.items, rt))
+		if curTokens+tokens <= budget {
+			cur.items = append(cur.items, g.items...)
+			labels = append(labels, g.label)
+			curTokens += tokens
+			continue
+		}
+		flush()
+		if tokens <= budget {
+			cur.items = append(cur.items, g.items...)
+			labels = append(labels, g.label)
+			curTokens = tokens
+			continue
+		}
+
+		// 单个分组超出上限，按条目拆分
+		part := 0
+		for _, item := range g.items {
+			t := EstimateTokens(formatWorkData([]WorkItem{item}, rt))
+			if len(cur.items) > 0 && curTokens+t > budget {
+				flush()
+			}
+			if len(cur.items) == 0 {
+				part++
+				labels = []string{fmt.Sprintf("%s（第 %d 段）", g.label, part)}
+			}
+			cur.items = append(cur.items, item)
+			curTokens += t
+		}
+		flush()
+	}
+	flush()
+	return chunks
+}
+
+// buildChunkPromptTemplate 构建分段总结的 Prompt 指令模板：将一段工作数据整理为工作条目，
+// 不输出标题和计划，供合并阶段使用。
+func buildChunkPromptTemplate(since, until time.Time, user string, rt ReportType, label string, index, total int) string {
+	labels := labelsForType(rt)
+	dateRange := fmt.Sprintf("%s ~ %s", since.Format("2006-01-02"), until.Format("2006-01-02"))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "你是一个%s。以下是%s的部分工作数据（%s，第 %d/%d 段），", labels.roleName, labels.reportName, label, index, total)
+	sb.WriteString("所有分段整理完成后会合并生成完整报告。请将这部分活动数据整理为工作条目。\n\n")
+	fmt.Fprintf(&sb, "日期范围: %s\n", dateRange)
+	fmt.Fprintf(&sb, "用户: %s\n\n", user)
+	sb.WriteString("请严格按照以下格式输出，不要添加任何额外内容:\n\n")
+	sb.WriteString("<日期> <工作描述>, <状态>, <URL>\n\n")
+	sb.WriteString("格式要求:\n")
+	sb.WriteString("- 每条记录一行，只输出工作条目，不要输出标题、计划或总结\n")
+	sb.WriteString("- 保留活动日期（没有日期时省略），同一 PR / Issue 的多条活动合并为一条，日期取最近一次\n")
+	sb.WriteString(workStatePromptRules)
+	if labels.workDesc != "" {
+		fmt.Fprintf(&sb, "- %s\n", labels.workDesc)
+	}
+	fmt.Fprintf(&sb, "\n=== %s数据（%s）===\n", labels.workTitle, label)
+
+	return sb.String()
+}
+
+// buildRegroupPromptTemplate 构建中间合并阶段的 Prompt 指令模板：将几段已整理的工作条目合并去重，
+// 按原格式输出，供下一轮合并使用。team 为 true 时条目按成员分节。
+func buildRegroupPromptTemplate(since, until time.Time, user string, rt ReportType, team bool, label string, index, total int) string {
+	labels := labelsForType(rt)
+	dateRange := fmt.Sprintf("%s ~ %s", since.Format("2006-01-02"), until.Format("2006-01-02"))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "你是一个%s。以下是%s的部分工作条目（%s，第 %d/%d 组），已按分段整理，", labels.roleName, labels.reportName, label, index, total)
+	sb.WriteString("所有分组合并完成后会生成完整报告。请将这些条目合并为一份工作条目。\n\n")
+	fmt.Fprintf(&sb, "日期范围: %s\n", dateRange)
+	if team {
+		fmt.Fprintf(&sb, "成员: %s\n\n", user)
+	} else {
+		fmt.Fprintf(&sb, "用户: %s\n\n", user)
+	}
+	sb.WriteString("请严格按照以下格式输出，不要添加任何额外内容:\n\n")
+	if team {
+		sb.WriteString("@<成员>\n")
+	}
+	sb.WriteString("<日期> <工作描述>, <状态>, <URL>\n\n")
+	sb.WriteString("格式要求:\n")
+	sb.WriteString("- 每条记录一行，只输出工作条目，不要输出标题、计划或总结\n")
+	if team {
+		sb.WriteString("- 每个成员一节，以 \"@<成员>\" 开头，不同成员的条目不要合并\n")
+	}
+	sb.WriteString("- 去除各段之间重复的条目（同一 URL 保留最近的状态和日期），其余条目保持原样，不要改写状态\n")
+	fmt.Fprintf(&sb, "\n=== %s条目（%s）===\n", labels.workTitle, label)
+
+	return sb.String()
+}
+
+// buildMergePrompt 构建合并阶段的 Prompt：完整报告的指令模板 + 各段整理后的工作条目 + 计划数据。
+func buildMergePrompt(parts []summaryPart, planItems []PlanItem, since, until time.Time, user string, rt ReportType, groupBy string) string {
+	labels := labelsForType(rt)
+	var sb strings.Builder
+	sb.WriteString(buildPromptTemplate(since, until, user, rt))
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "=== %s数据（已按%s分 %d 段整理）===\n", labels.workTitle, groupBy, len(parts))
+	sb.WriteString("以下工作条目已按分段整理为 \"<日期> <工作描述>, <状态>, <URL>\" 格式。")
+	sb.WriteString("请合并为最终报告：去除各段之间重复的条目（同一 URL 保留最近的状态），按上述格式要求输出。\n")
+	writeSummaryParts(&sb, parts)
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "=== %s数据 ===\n", labels.planTitle)
+	sb.WriteString(formatPlanData(planItems))
+
+	return sb.String()
+}
@@ -0,0 +1,277 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"本周工作", 4},
+		{"PR 合并", 3},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.s); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+// testIssues 构造 repo 下编号为 1..n 的 Issue 条目。
+func testIssues(repo string, n int) []WorkItem {
+	items := make([]WorkItem, n)
+	for i := range items {
+		items[i] = WorkItem{
+			Type:   "issue",
+			Repo:   repo,
+			Number: i + 1,
+			Title:  "Fix flaky test",
+			State:  "closed",
+			URL:    fmt.Sprintf("https://github.com/%s/issues/%d", repo, i+1),
+		}
+	}
+	return items
+}
+
+// chunkLabels 返回各分段的说明及条目数，如 "a/b:2"。
+func chunkLabels(chunks []workGroup) []string {
+	labels := make([]string, len(chunks))
+	for i, c := range chunks {
+		labels[i] = fmt.Sprintf("%s:%d", c.label, len(c.items))
+	}
+	return labels
+}
+
+func TestPackWorkGroups(t *testing.T) {
+	a := workGroup{label: "o/a", items: testIssues("o/a", 2)}
+	b := workGroup{label: "o/b", items: testIssues("o/b", 1)}
+	big := workGroup{label: "o/big", items: testIssues("o/big", 5)}
+	c := workGroup{label: "o/c", items: testIssues("o/c", 1)}
+
+	tokens := func(groups ...workGroup) int {
+		n := 0
+		for _, g := range groups {
+			n += EstimateTokens(formatWorkData(g.items, ReportWeekly))
+		}
+		return n
+	}
+	itemTokens := EstimateTokens(formatWorkData(big.items[:1], ReportWeekly))
+
+	tests := []struct {
+		name   string
+		groups []workGroup
+		budget int
+		want   []string
+	}{
+		{
+			name:   "exact fit",
+			groups: []workGroup{a, b},
+			budget: tokens(a, b),
+			want:   []string{"o/a, o/b:3"},
+		},
+		{
+			name:   "one token short",
+			groups: []workGroup{a, b},
+			budget: tokens(a, b) - 1,
+			want:   []string{"o/a:2", "o/b:1"},
+		},
+		{
+			// 超出上限的分组按条目拆分，前后的分组不与拆分段合并
+			name:   "oversized group",
+			groups: []workGroup{b, big, c},
+			budget: 2 * itemTokens,
+			want:   []string{"o/b:1", "o/big（第 1 段）:2", "o/big（第 2 段）:2", "o/big（第 3 段）:1", "o/c:1"},
+		},
+		{
+			// 单个条目超出上限时仍单独成段
+			name:   "item larger than budget",
+			groups: []workGroup{{label: "o/big", items: big.items[:2]}},
+			budget: 1,
+			want:   []string{"o/big（第 1 段）:1", "o/big（第 2 段）:1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := packWorkGroups(tt.groups, ReportWeekly, tt.budget)
+			if got := chunkLabels(chunks); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("packWorkGroups() = %q, want %q", got, tt.want)
+			}
+
+			// 每个条目恰好出现一次，且保持原顺序
+			var want, got []string
+			for _, g := range tt.groups {
+				for _, item := range g.items {
+					want = append(want, item.URL)
+				}
+			}
+			for _, c := range chunks {
+				for _, item := range c.items {
+					got = append(got, item.URL)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("packWorkGroups() items = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPackSummaryParts(t *testing.T) {
+	part := func(label string, tokens int) summaryPart {
+		return summaryPart{label: label, text: strings.Repeat("x", tokens*4)}
+	}
+	tests := []struct {
+		name   string
+		parts  []summaryPart
+		budget int
+		want   []string
+	}{
+		{
+			name:   "exact fit",
+			parts:  []summaryPart{part("a", 10), part("b", 10), part("c", 10)},
+			budget: 30,
+			want:   []string{"a,b,c"},
+		},
+		{
+			name:   "split",
+			parts:  []summaryPart{part("a", 10), part("b", 10), part("c", 10)},
+			budget: 29,
+			want:   []string{"a,b", "c"},
+		},
+		{
+			// 每组至少两段，即使超出上限
+			name:   "oversized parts",
+			parts:  []summaryPart{part("a", 50), part("b", 50), part("c", 50), part("d", 50), part("e", 50)},
+			budget: 10,
+			want:   []string{"a,b", "c,d", "e"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, g := range packSummaryParts(tt.parts, tt.budget) {
+				labels := make([]string, len(g))
+				for i, p := range g {
+					labels[i] = p.label
+				}
+				got = append(got, strings.Join(labels, ","))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("packSummaryParts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testMergePlan 构造一个包含 n 段、上限为 maxTokens 的分段计划，合并 Prompt 依次列出各段结果。
+func testMergePlan(n, maxTokens int) SummaryPlan {
+	plan := SummaryPlan{
+		maxTokens: maxTokens,
+		final: func(parts []summaryPart) string {
+			var sb strings.Builder
+			sb.WriteString("final\n")
+			writeSummaryParts(&sb, parts)
+			return sb.String()
+		},
+		regroup: func(label string, index, total int) string {
+			return fmt.Sprintf("regroup %s %d/%d\n", label, index, total)
+		},
+	}
+	for i := range n {
+		plan.Chunks = append(plan.Chunks, SummaryChunk{Label: fmt.Sprintf("p%d", i+1)})
+	}
+	return plan
+}
+
+func TestSummaryPlanMerge(t *testing.T) {
+	plan := testMergePlan(5, 100)
+	partial := strings.Repeat("x", 160) // 40 tokens
+
+	// 每轮分段数严格减少，各段结果恰好出现在一个分组中
+	rounds := 0
+	for len(plan.Chunks) > 0 {
+		rounds++
+		if rounds > 5 {
+			t.Fatal("Merge() did not converge")
+		}
+		partials := make([]string, len(plan.Chunks))
+		for i := range partials {
+			partials[i] = partial
+		}
+		prev := plan.Chunks
+		plan = plan.Merge(partials)
+		if len(plan.Chunks) >= len(prev) {
+			t.Fatalf("round %d: chunks %d -> %d, want fewer", rounds, len(prev), len(plan.Chunks))
+		}
+
+		var labels []string
+		for _, c := range plan.Chunks {
+			labels = append(labels, c.Label)
+			if !strings.HasPrefix(c.Prompt, "regroup "+c.Label) {
+				t.Errorf("chunk prompt = %q, want regroup template", c.Prompt)
+			}
+		}
+		if len(plan.Chunks) > 0 {
+			var want []string
+			for _, c := range prev {
+				want = append(want, c.Label)
+			}
+			if got := strings.Join(labels, ", "); got != strings.Join(want, ", ") {
+				t.Errorf("round %d labels = %q, want %q", rounds, got, strings.Join(want, ", "))
+			}
+		}
+	}
+
+	// 5 段 -> 3 组 -> 2 组 -> 最终报告
+	if rounds != 3 {
+		t.Errorf("rounds = %d, want 3", rounds)
+	}
+	if !strings.HasPrefix(plan.Prompt, "final\n") || plan.final != nil || plan.regroup != nil {
+		t.Errorf("final plan = %+v", plan)
+	}
+	for _, label := range []string{"p1, p2, p3, p4", "p5"} {
+		if n := strings.Count(plan.Prompt, "--- "+label+" ---"); n != 1 {
+			t.Errorf("final prompt contains %q %d times, want 1", label, n)
+		}
+	}
+}
+
+func TestSummaryPlanMergeFits(t *testing.T) {
+	plan := testMergePlan(3, 1000)
+	next := plan.Merge([]string{"  a  ", "b\n"})
+	if len(next.Chunks) != 0 {
+		t.Fatalf("Merge() chunks = %d, want 0", len(next.Chunks))
+	}
+	// 缺少的结果按空文本处理，结果去除首尾空白
+	want := "final\n\n--- p1 ---\na\n\n--- p2 ---\nb\n\n--- p3 ---\n"
+	if next.Prompt != want {
+		t.Errorf("Merge() prompt = %q, want %q", next.Prompt, want)
+	}
+
+	// 未分段的计划直接返回原 Prompt
+	if got := (SummaryPlan{Prompt: "p"}).Merge(nil); got.Prompt != "p" || len(got.Chunks) != 0 {
+		t.Errorf("Merge() on unchunked plan = %+v", got)
+	}
+}
+
+func TestMergePromptsDeduplicate(t *testing.T) {
+	const rule = "去除各段之间重复的条目"
+	since := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 9, 30, 23, 59, 59, 0, time.UTC)
+	regroup := buildRegroupPromptTemplate(since, until, "alice", ReportMonthly, false, "o/a, o/b", 1, 2)
+	merge := buildMergePrompt([]summaryPart{{label: "o/a", text: "x"}}, nil, since, until, "alice", ReportMonthly, "仓库")
+	for name, prompt := range map[string]string{"regroup": regroup, "merge": merge} {
+		if !strings.Contains(prompt, rule) {
+			t.Errorf("%s prompt missing de-duplication rule", name)
+		}
+	}
+}
@@ -3,8 +3,13 @@ package report
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +21,7 @@ import (
 
 // RepoReport 保存单个仓库的所有收集数据。
 type RepoReport struct {
+	Host           string                          // 仓库所在主机（默认主机为空）
 	Owner          string                          // 仓库所有者
 	Repo           string                          // 仓库名称
 	Issues         []*gh.Issue                     // Issue 列表
@@ -23,14 +29,149 @@ type RepoReport struct {
 	IssueComments  []*gh.IssueComment              // Issue 评论列表
 	ReviewComments []*gh.PullRequestComment         // PR Review 评论列表
 	Reviews        map[int][]*gh.PullRequestReview // PR Review 列表，以 PR 编号为键
+	CI             map[int]github.CIStatus         // PR head 提交的 CI 状态，以 PR 编号为键（没有检查的 PR 不出现）
+	LinkedIssues   map[int][]github.LinkedIssue    // PR 关联的同仓库 Issue，以 PR 编号为键
+	Commits        []*gh.RepositoryCommit          // 未被已列出 PR 覆盖的提交（直接推送等）
+	ReviewRequests []ReviewRequest                 // 正在等待用户 Review 的 PR
 	Projects       []github.Project                // 关联的 Projects v2 项目
+
+	webHost string // 仓库网页所在主机（Host 为空时为默认客户端的主机，如 GitHub Enterprise Server），为空时为 github.com
+}
+
+// ReviewRequest 表示一个正在等待用户 Review 的 PR。
+type ReviewRequest struct {
+	Number      int
+	Title       string
+	URL         string
+	Author      string    // PR 作者
+	Reviewer    string    // 被请求 Review 的用户
+	Team        string    // 通过团队请求时的团队 slug，直接请求用户时为空
+	RequestedAt time.Time // 最近一次请求时间
+}
+
+// FullName 返回仓库全名 "owner/repo"，非默认主机的仓库带主机前缀 "host/owner/repo"。
+func (rr RepoReport) FullName() string {
+	if rr.Host == "" {
+		return rr.Owner + "/" + rr.Repo
+	}
+	return rr.Host + "/" + rr.Owner + "/" + rr.Repo
+}
+
+// ownsURL 判断网页地址（如项目工作项的链接）是否属于该仓库，按主机、owner 和仓库名前缀匹配。
+func (rr RepoReport) ownsURL(u string) bool {
+	host := rr.Host
+	if host == "" {
+		host = rr.webHost
+	}
+	if host == "" {
+		host = github.DefaultHost
+	}
+	return github.URLInRepo(u, host+"/"+rr.Owner+"/"+rr.Repo)
 }
 
 // Options 指定数据收集的参数。
 type Options struct {
-	Repos []string // 仓库列表，格式为 "owner/repo"
-	Days  int      // 查看最近几天的活动
-	User  string   // 按用户过滤（为空则不过滤）
+	Repos []string  // 仓库列表，格式为 "owner/repo" 或 "host/owner/repo"
+	Days  int       // 查看最近几天的活动（Since 为空时使用）
+	Since time.Time // 数据获取起点（为空时使用 Until 前推 Days 天）
+	Until time.Time // 数据获取终点，之后才发生的活动被排除（为空时使用当前时间）
+	User  string    // 按用户过滤（为空则不过滤）
+	Users []string  // 按用户集合过滤（团队模式，与 User 同时为空则不过滤）
+
+	// RESTReviews 为 true 时使用 REST 接口逐个获取 PR 的 Review（每个 PR 一次请求），
+	// 默认通过 GraphQL 批量获取 PR 及其 Review。
+	RESTReviews bool
+
+	// CommitBranches 除默认分支外还要获取提交的分支（glob 模式，如 "release/*"），
+	// 只获取最新提交不早于数据获取起点的分支。为 nil 时使用 DefaultCommitBranches，为空列表时只获取默认分支。
+	CommitBranches []string
+
+	// Projects 额外指定的 Projects v2 项目，格式为 "owner/number" 或 "host/owner/number"。
+	// owner 可以是组织或用户（个人项目），项目关联到同一主机上的所有仓库。
+	Projects []string
+
+	// ProjectOptions 选择仓库所有者下的哪些项目，以及迭代和状态取自哪个字段。
+	ProjectOptions github.ProjectOptions
+
+	// Clients 按主机名指定其他 GitHub 主机（如 GitHub Enterprise Server）的客户端。
+	// 格式为 "host/owner/repo" 的仓库使用对应主机的客户端，其余仓库使用 Collect 的 client 参数。
+	Clients map[string]*github.Client
+}
+
+// DefaultCommitBranches 是默认获取提交的分支（除默认分支外），覆盖直接推送到发布分支和热修复分支的提交。
+var DefaultCommitBranches = []string{"release/*", "hotfix/*"}
+
+// ParseRepo 解析仓库标识，支持 "owner/repo" 和 "host/owner/repo" 两种格式。
+// 未指定主机时 host 为空，指向 GitHub.com 的主机名统一为 github.DefaultHost。
+func ParseRepo(s string) (host, owner, repo string, err error) {
+	parts := strings.Split(s, "/")
+	switch {
+	case len(parts) == 2:
+		owner, repo = parts[0], parts[1]
+	case len(parts) == 3:
+		host, owner, repo = parts[0], parts[1], parts[2]
+		if github.IsDefaultHost(host) {
+			host = github.DefaultHost
+		}
+	}
+	if owner == "" || repo == "" {
+		return "", "", "", fmt.Errorf("invalid repo format %q, expected owner/repo or host/owner/repo", s)
+	}
+	return host, owner, repo, nil
+}
+
+// ParseProject 解析项目标识，支持 "owner/number" 和 "host/owner/number" 两种格式。
+// 主机的处理与 ParseRepo 相同。
+func ParseProject(s string) (host, owner string, number int, err error) {
+	host, owner, num, err := ParseRepo(s)
+	if err == nil {
+		number, err = strconv.Atoi(num)
+	}
+	if err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("invalid project format %q, expected owner/number or host/owner/number", s)
+	}
+	return host, owner, number, nil
+}
+
+// clientFor 返回主机对应的客户端，host 为空或与默认客户端的主机相同时返回 client。
+func (o Options) clientFor(client *github.Client, host string) (*github.Client, error) {
+	if host == "" || host == client.Host() {
+		return client, nil
+	}
+	if c, ok := o.Clients[host]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("no GitHub client configured for host %q", host)
+}
+
+// userFilter 返回按 User / Users 过滤的判断函数。
+// 两者均为空时不过滤；均不为空时匹配任一即可。
+func (o Options) userFilter() func(login string) bool {
+	if o.User == "" && len(o.Users) == 0 {
+		return func(string) bool { return true }
+	}
+	set := make(map[string]bool, len(o.Users)+1)
+	if o.User != "" {
+		set[o.User] = true
+	}
+	for _, u := range o.Users {
+		set[u] = true
+	}
+	return func(login string) bool { return set[login] }
+}
+
+// logins 返回 User / Users 中去重后的用户列表。
+func (o Options) logins() []string {
+	var logins []string
+	seen := make(map[string]bool)
+	for _, u := range append([]string{o.User}, o.Users...) {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		logins = append(logins, u)
+	}
+	return logins
 }
 
 // Progress 报告数据收集进度的接口。
@@ -44,44 +185,137 @@ type Progress interface {
 
 // Collect 收集指定仓库的所有活动数据。
 // 使用三层并发策略加速数据获取：组织 Projects 与仓库数据并发、仓库内 4 个接口并发、PR Review 并发。
+// 默认通过 GraphQL 随 PR 一起批量获取 Review，不需要第三层；Options.RESTReviews 为 true 时使用 REST 逐个获取。
+// 指定 User / Users 时，还会通过 Search API 获取正在等待这些用户 Review 的 PR（until 早于今天时不获取）。
+// Projects 只获取报告仓库中、与 until 相关的迭代中的工作项。
 // progress 参数可选，用于报告每个仓库的数据获取进度。
 func Collect(ctx context.Context, client *github.Client, opts Options, progress Progress) ([]RepoReport, error) {
-	since := time.Now().AddDate(0, 0, -opts.Days)
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	since := opts.Since
+	if since.IsZero() {
+		since = until.AddDate(0, 0, -opts.Days)
+	}
+	matchUser := opts.userFilter()
 
-	// 解析仓库列表，收集唯一 owner
+	// 解析仓库列表，收集唯一 owner（不同主机上的同名 owner 分别处理）
 	type repoInfo struct {
+		host   string
+		owner  string
+		repo   string
+		client *github.Client
+	}
+	type ownerKey struct {
+		host  string
 		owner string
-		repo  string
 	}
 	repos := make([]repoInfo, len(opts.Repos))
-	owners := make(map[string]struct{})
+	owners := make(map[ownerKey]*github.Client)
+	ownerRepos := make(map[ownerKey][]string) // 用于只获取相关仓库中的项目工作项
+	hostRepos := make(map[string][]string)
 	for i, fullRepo := range opts.Repos {
-		parts := strings.SplitN(fullRepo, "/", 2)
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid repo format %q, expected owner/repo", fullRepo)
+		host, owner, repo, err := ParseRepo(fullRepo)
+		if err != nil {
+			return nil, err
+		}
+		c, err := opts.clientFor(client, host)
+		if err != nil {
+			return nil, err
+		}
+		if host == client.Host() {
+			host = ""
 		}
-		repos[i] = repoInfo{owner: parts[0], repo: parts[1]}
-		owners[parts[0]] = struct{}{}
+		repos[i] = repoInfo{host: host, owner: owner, repo: repo, client: c}
+		owners[ownerKey{host, owner}] = c
+		ownerRepos[ownerKey{host, owner}] = append(ownerRepos[ownerKey{host, owner}], owner+"/"+repo)
+		hostRepos[host] = append(hostRepos[host], owner+"/"+repo)
+	}
+
+	// 配置中指定的项目，按主机关联到仓库
+	type projectRef struct {
+		host    string
+		owner   string
+		number  int
+		client  *github.Client
+		project *github.Project // 获取失败时为 nil
+	}
+	projectRefs := make([]projectRef, len(opts.Projects))
+	for i, s := range opts.Projects {
+		host, owner, number, err := ParseProject(s)
+		if err != nil {
+			return nil, err
+		}
+		c, err := opts.clientFor(client, host)
+		if err != nil {
+			return nil, err
+		}
+		if host == client.Host() {
+			host = ""
+		}
+		projectRefs[i] = projectRef{host: host, owner: owner, number: number, client: c}
 	}
 
 	// 第一层并发：同时获取组织 Projects 和各仓库数据（独立 WaitGroup）
 	var orgWg sync.WaitGroup
 	var mu sync.Mutex
-	orgProjects := make(map[string][]github.Project)
-	for owner := range owners {
+	orgProjects := make(map[ownerKey][]github.Project)
+	// 只获取报告仓库中、与 until 相关的迭代中的工作项
+	projectOpts := opts.ProjectOptions
+	projectOpts.Until = until
+	for i := range projectRefs {
+		orgWg.Add(1)
+		go func(ref *projectRef) {
+			defer orgWg.Done()
+			refOpts := projectOpts
+			refOpts.Repos = hostRepos[ref.host]
+			project, err := ref.client.GetProject(ctx, ref.owner, ref.number, refOpts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not fetch project %s/%d: %v\n", ref.owner, ref.number, err)
+				return
+			}
+			if len(opts.Users) > 0 {
+				project = filterProjectAssignees([]github.Project{project}, matchUser)[0]
+			}
+			ref.project = &project
+		}(&projectRefs[i])
+	}
+	for key, c := range owners {
 		orgWg.Add(1)
-		go func(owner string) {
+		go func(key ownerKey, c *github.Client) {
 			defer orgWg.Done()
-			projects, err := client.ListProjects(ctx, owner)
+			ownerOpts := projectOpts
+			ownerOpts.Repos = ownerRepos[key]
+			projects, err := c.ListProjects(ctx, key.owner, ownerOpts)
 			mu.Lock()
 			defer mu.Unlock()
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: could not fetch projects for %s: %v\n", owner, err)
-				orgProjects[owner] = nil
+				fmt.Fprintf(os.Stderr, "Warning: could not fetch projects for %s: %v\n", key.owner, err)
+				orgProjects[key] = nil
 			} else {
-				orgProjects[owner] = projects
+				if len(opts.Users) > 0 {
+					projects = filterProjectAssignees(projects, matchUser)
+				}
+				orgProjects[key] = projects
 			}
-		}(owner)
+		}(key, c)
+	}
+
+	q := repoQuery{
+		since:       since,
+		until:       until,
+		matchUser:   matchUser,
+		restReviews: opts.RESTReviews,
+
+		commitBranches: opts.CommitBranches,
+	}
+	if q.commitBranches == nil {
+		q.commitBranches = DefaultCommitBranches
+	}
+	// 单用户时由 API 按作者过滤提交，团队模式下获取全部提交后再按成员过滤
+	if opts.User != "" && len(opts.Users) == 0 {
+		q.commitAuthor = opts.User
 	}
 
 	// 并发收集每个仓库的数据
@@ -90,15 +324,17 @@ func Collect(ctx context.Context, client *github.Client, opts Options, progress
 	repoErrs := make([]error, len(repos))
 	for i, ri := range repos {
 		repoWg.Add(1)
-		go func(idx int, owner, repo string) {
+		go func(idx int, ri repoInfo) {
 			defer repoWg.Done()
-			rr, err := collectRepo(ctx, client, owner, repo, since, opts.User, progress, idx)
+			rr, err := collectRepo(ctx, ri.client, ri.owner, ri.repo, q, progress, idx)
 			if err != nil {
 				repoErrs[idx] = err
 				return
 			}
+			rr.Host = ri.host
+			rr.webHost = ri.client.Host()
 			reports[idx] = *rr
-		}(i, ri.owner, ri.repo)
+		}(i, ri)
 	}
 
 	// 先等待仓库数据（进度条跟踪此阶段）
@@ -111,12 +347,48 @@ func Collect(ctx context.Context, client *github.Client, opts Options, progress
 		}
 	}
 
+	// 等待用户 Review 的 PR：按客户端（主机）分别搜索，再分配到对应仓库。
+	// Search 只能查询当前的请求状态，until 早于今天（回溯历史时间范围）时不获取，避免把现在的请求计入过去的报告
+	if logins := opts.logins(); len(logins) > 0 && !isBackfill(until, time.Now()) {
+		byClient := make(map[*github.Client]map[string]int)
+		for i, ri := range repos {
+			if byClient[ri.client] == nil {
+				byClient[ri.client] = make(map[string]int)
+			}
+			byClient[ri.client][strings.ToLower(ri.owner+"/"+ri.repo)] = i
+		}
+		for c, repoIndex := range byClient {
+			requests, err := collectReviewRequests(ctx, c, logins, repoIndex, until)
+			if err != nil {
+				return nil, err
+			}
+			for idx, reqs := range requests {
+				reports[idx].ReviewRequests = reqs
+			}
+		}
+	}
+
 	// 等待组织 Projects（通常与仓库数据同步完成或更早）
 	orgWg.Wait()
 
 	// 关联 Projects 到对应仓库，并推进进度条最后一步
+	// 配置中指定的项目关联到同一主机上的所有仓库，已在仓库所有者的项目中出现的不重复关联
 	for i := range reports {
-		reports[i].Projects = orgProjects[reports[i].Owner]
+		projects := append([]github.Project(nil), orgProjects[ownerKey{reports[i].Host, reports[i].Owner}]...)
+		owned := make(map[int]bool, len(projects))
+		for _, p := range projects {
+			owned[p.Number] = true
+		}
+		for _, ref := range projectRefs {
+			if ref.project == nil || ref.host != reports[i].Host {
+				continue
+			}
+			if strings.EqualFold(ref.owner, reports[i].Owner) && owned[ref.number] {
+				continue
+			}
+			projects = append(projects, *ref.project)
+		}
+		reports[i].Projects = projects
 		if progress != nil {
 			progress.Increment(i)
 		}
@@ -125,30 +397,188 @@ func Collect(ctx context.Context, client *github.Client, opts Options, progress
 	return reports, nil
 }
 
+// isBackfill 判断 until 是否早于 now 所在当天的零点（按 until 的时区），即报告回溯的是历史时间范围。
+func isBackfill(until, now time.Time) bool {
+	now = now.In(until.Location())
+	return until.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+}
+
+// filterProjectAssignees 只保留 Assignees 中包含匹配用户的项目工作项（团队模式使用）。
+// 返回新的切片，不修改原项目数据。
+func filterProjectAssignees(projects []github.Project, matchUser func(string) bool) []github.Project {
+	filtered := make([]github.Project, len(projects))
+	for i, p := range projects {
+		filtered[i] = p
+		filtered[i].Items = nil
+		for _, item := range p.Items {
+			for _, a := range item.Assignees {
+				if matchUser(a) {
+					filtered[i].Items = append(filtered[i].Items, item)
+					break
+				}
+			}
+		}
+	}
+	return filtered
+}
+
+// collectReviewRequests 搜索正在等待 logins 中用户 Review 的 PR，只保留 repoIndex 中的仓库，
+// 并通过时间线事件确定最近一次请求的时间。返回结果以仓库在 repoIndex 中的索引为键。
+// 在 until 之后才发起的请求会被排除。
+func collectReviewRequests(ctx context.Context, client *github.Client, logins []string, repoIndex map[string]int, until time.Time) (map[int][]ReviewRequest, error) {
+	type target struct {
+		idx         int
+		owner, repo string
+		issue       *gh.Issue
+		login       string
+	}
+	var targets []target
+	for _, login := range logins {
+		issues, err := client.SearchReviewRequests(ctx, login)
+		if err != nil {
+			return nil, fmt.Errorf("searching review requests for %s: %w", login, err)
+		}
+		for _, issue := range issues {
+			full := github.RepoFromURL(issue.GetRepositoryURL())
+			idx, ok := repoIndex[strings.ToLower(full)]
+			if !ok {
+				continue
+			}
+			owner, repo, _ := strings.Cut(full, "/")
+			targets = append(targets, target{idx: idx, owner: owner, repo: repo, issue: issue, login: login})
+		}
+	}
+
+	// 并发获取每个 PR 的 review_requested 事件
+	events := make([][]*gh.Timeline, len(targets))
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(idx int, t target) {
+			defer wg.Done()
+			events[idx], errs[idx] = client.ListReviewRequestEvents(ctx, t.owner, t.repo, t.issue.GetNumber())
+		}(i, t)
+	}
+	wg.Wait()
+
+	teams := &teamMembers{client: client, members: make(map[string]map[string]bool)}
+	result := make(map[int][]ReviewRequest)
+	for i, t := range targets {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("listing review requests for %s/%s#%d: %w", t.owner, t.repo, t.issue.GetNumber(), errs[i])
+		}
+		requestedAt, team := lastReviewRequest(events[i], t.login, func(slug string) bool {
+			return teams.has(ctx, t.owner, slug, t.login)
+		})
+		if requestedAt.IsZero() {
+			requestedAt = t.issue.GetCreatedAt().Time
+		}
+		if requestedAt.After(until) {
+			continue
+		}
+		result[t.idx] = append(result[t.idx], ReviewRequest{
+			Number:      t.issue.GetNumber(),
+			Title:       t.issue.GetTitle(),
+			URL:         t.issue.GetHTMLURL(),
+			Author:      t.issue.GetUser().GetLogin(),
+			Reviewer:    t.login,
+			Team:        team,
+			RequestedAt: requestedAt,
+		})
+	}
+	return result, nil
+}
+
+// lastReviewRequest 从 review_requested 事件中找出对 login 的最近一次请求，返回请求时间和团队 slug。
+// 没有直接请求该用户的事件时，取最近一次对用户所在团队（inTeam 返回 true）的请求。
+func lastReviewRequest(events []*gh.Timeline, login string, inTeam func(slug string) bool) (time.Time, string) {
+	var direct, viaTeam *gh.Timeline
+	for _, e := range events {
+		switch {
+		case strings.EqualFold(e.GetReviewer().GetLogin(), login):
+			direct = e
+		case e.RequestedTeam != nil && inTeam(e.GetRequestedTeam().GetSlug()):
+			viaTeam = e
+		}
+	}
+	if direct != nil {
+		return direct.GetCreatedAt().Time, ""
+	}
+	if viaTeam != nil {
+		return viaTeam.GetCreatedAt().Time, viaTeam.GetRequestedTeam().GetSlug()
+	}
+	return time.Time{}, ""
+}
+
+// teamMembers 缓存组织团队的成员，用于判断团队 Review 请求是否包含用户。每个团队只查询一次。
+type teamMembers struct {
+	client  *github.Client
+	members map[string]map[string]bool // "org/slug" -> 小写 login 集合，获取失败时为 nil
+}
+
+// has 判断 login 是否属于组织 org 的团队 slug（包含子团队成员）。
+// 无法获取团队成员时（如 Token 缺少 read:org 权限）返回 true：Search 结果已确认用户直接或通过所在团队被请求。
+func (m *teamMembers) has(ctx context.Context, org, slug, login string) bool {
+	key := strings.ToLower(org + "/" + slug)
+	members, ok := m.members[key]
+	if !ok {
+		logins, err := m.client.ListTeamMembers(ctx, org, slug)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch members of team %s/%s: %v\n", org, slug, err)
+		} else {
+			members = make(map[string]bool, len(logins))
+			for _, l := range logins {
+				members[strings.ToLower(l)] = true
+			}
+		}
+		m.members[key] = members
+	}
+	return members == nil || members[strings.ToLower(login)]
+}
+
+// repoQuery 是收集单个仓库数据时使用的参数。
+type repoQuery struct {
+	since        time.Time
+	until        time.Time
+	matchUser    func(string) bool // 判断作者是否需要保留
+	restReviews  bool              // 使用 REST 逐个获取 PR Review
+	commitAuthor string            // 按作者获取提交（为空则获取全部提交）
+
+	commitBranches []string // 除默认分支外获取提交的分支（glob 模式）
+}
+
 // collectRepo 并发收集单个仓库的所有活动数据。
-// 第二层并发：同时获取 Issues、PRs、Issue Comments、Review Comments。
-// 第三层并发：并发获取每个 PR 的 Review。
-func collectRepo(ctx context.Context, client *github.Client, owner, repo string, since time.Time, user string, progress Progress, repoIndex int) (*RepoReport, error) {
+// 第二层并发：同时获取 Issues、PRs、Issue Comments、Review Comments、Commits。
+// 第三层并发：restReviews 为 true 时并发获取每个 PR 的 Review，否则 PR 与 Review 通过 GraphQL 一起获取。
+// 在 until 之后才创建的 Issue、PR、评论和提交会被排除；matchUser 判断作者是否需要保留。
+func collectRepo(ctx context.Context, client *github.Client, owner, repo string, q repoQuery, progress Progress, repoIndex int) (*RepoReport, error) {
+	since, until, matchUser, restReviews := q.since, q.until, q.matchUser, q.restReviews
 	rr := &RepoReport{
-		Owner:   owner,
-		Repo:    repo,
-		Reviews: make(map[int][]*gh.PullRequestReview),
+		Owner:        owner,
+		Repo:         repo,
+		Reviews:      make(map[int][]*gh.PullRequestReview),
+		CI:           make(map[int]github.CIStatus),
+		LinkedIssues: make(map[int][]github.LinkedIssue),
 	}
 
-	// 第二层并发：4 个列表接口同时发起
+	// 第二层并发：5 个列表接口同时发起
 	var (
 		wg             sync.WaitGroup
 		rawIssues      []*gh.Issue
 		rawPRs         []*gh.PullRequest
+		rawDetails     *github.PullRequestDetails
 		rawComments    []*gh.IssueComment
 		rawRevComments []*gh.PullRequestComment
+		rawCommits     []*gh.RepositoryCommit
 		errIssues      error
 		errPRs         error
 		errComments    error
 		errRevComments error
+		errCommits     error
 	)
 
-	wg.Add(4)
+	wg.Add(5)
 
 	go func() {
 		defer wg.Done()
@@ -160,7 +590,14 @@ func collectRepo(ctx context.Context, client *github.Client, owner, repo string,
 
 	go func() {
 		defer wg.Done()
-		rawPRs, errPRs = client.ListPullRequests(ctx, owner, repo, since)
+		if restReviews {
+			rawPRs, errPRs = client.ListPullRequests(ctx, owner, repo, since)
+		} else {
+			rawDetails, errPRs = client.ListPullRequestsWithReviews(ctx, owner, repo, since)
+			if errPRs == nil {
+				rawPRs = rawDetails.PullRequests
+			}
+		}
 		if progress != nil {
 			progress.Increment(repoIndex)
 		}
@@ -182,6 +619,14 @@ func collectRepo(ctx context.Context, client *github.Client, owner, repo string,
 		}
 	}()
 
+	go func() {
+		defer wg.Done()
+		rawCommits, errCommits = listCommits(ctx, client, owner, repo, q)
+		if progress != nil {
+			progress.Increment(repoIndex)
+		}
+	}()
+
 	wg.Wait()
 
 	// 检查错误
@@ -197,13 +642,20 @@ func collectRepo(ctx context.Context, client *github.Client, owner, repo string,
 	if errRevComments != nil {
 		return nil, fmt.Errorf("listing review comments for %s/%s: %w", owner, repo, errRevComments)
 	}
+	// 空仓库的提交列表返回 409，视为没有提交
+	if errCommits != nil && !isEmptyRepoError(errCommits) {
+		return nil, fmt.Errorf("listing commits for %s/%s: %w", owner, repo, errCommits)
+	}
 
 	// 按用户过滤 Issues（排除 PR）
 	for _, issue := range rawIssues {
 		if issue.IsPullRequest() {
 			continue
 		}
-		if user == "" || issue.GetUser().GetLogin() == user {
+		if issue.GetCreatedAt().After(until) {
+			continue
+		}
+		if matchUser(issue.GetUser().GetLogin()) {
 			rr.Issues = append(rr.Issues, issue)
 		}
 	}
@@ -211,10 +663,10 @@ func collectRepo(ctx context.Context, client *github.Client, owner, repo string,
 	// 按用户过滤 Pull Requests
 	// 仅保留在时间范围内有实际活动（创建、合并、关闭）或仍处于 open 状态的 PR
 	for _, pr := range rawPRs {
-		if user != "" && pr.GetUser().GetLogin() != user {
+		if !matchUser(pr.GetUser().GetLogin()) {
 			continue
 		}
-		if !prHasActivitySince(pr, since) {
+		if !prHasActivitySince(pr, since, until) {
 			continue
 		}
 		rr.PullRequests = append(rr.PullRequests, pr)
@@ -222,52 +674,113 @@ func collectRepo(ctx context.Context, client *github.Client, owner, repo string,
 
 	// 按用户过滤 Issue Comments
 	for _, c := range rawComments {
-		if user == "" || c.GetUser().GetLogin() == user {
+		if c.GetCreatedAt().After(until) {
+			continue
+		}
+		if matchUser(c.GetUser().GetLogin()) {
 			rr.IssueComments = append(rr.IssueComments, c)
 		}
 	}
 
 	// 按用户过滤 Review Comments
 	for _, rc := range rawRevComments {
-		if user == "" || rc.GetUser().GetLogin() == user {
+		if rc.GetCreatedAt().After(until) {
+			continue
+		}
+		if matchUser(rc.GetUser().GetLogin()) {
 			rr.ReviewComments = append(rr.ReviewComments, rc)
 		}
 	}
 
-	// 第三层并发：并发获取每个 PR 的 Review
-	if len(rr.PullRequests) > 0 {
-		// 更新总步数：4 个 API 调用 + N 个 Review + 1 完成步 + 1 Projects 关联步
+	// GraphQL 路径：Review、CI 状态和关联 Issue 已随 PR 一起获取
+	if !restReviews {
+		for _, pr := range rr.PullRequests {
+			if reviews := rawDetails.Reviews[pr.GetNumber()]; len(reviews) > 0 {
+				rr.Reviews[pr.GetNumber()] = reviews
+			}
+			if ci, ok := rawDetails.CI[pr.GetNumber()]; ok {
+				rr.CI[pr.GetNumber()] = ci
+			}
+			if linked := rawDetails.LinkedIssues[pr.GetNumber()]; len(linked) > 0 {
+				rr.LinkedIssues[pr.GetNumber()] = linked
+			}
+		}
+	} else {
+		// REST 路径：从 PR 描述中的关闭关键字（如 "fixes #12"）解析关联 Issue
+		for _, pr := range rr.PullRequests {
+			if linked := parseLinkedIssues(pr, rawIssues); len(linked) > 0 {
+				rr.LinkedIssues[pr.GetNumber()] = linked
+			}
+		}
+	}
+
+	// 第三层并发：REST 路径下并发获取每个 PR 的 Review 和 head 提交的 CI 状态
+	if restReviews && len(rr.PullRequests) > 0 {
+		// 更新总步数：5 个 API 调用 + N 个 PR + 1 完成步 + 1 Projects 关联步
 		if progress != nil {
-			progress.SetTotal(repoIndex, 4+len(rr.PullRequests)+2)
+			progress.SetTotal(repoIndex, 5+len(rr.PullRequests)+2)
 		}
 
 		reviewResults := make([][]*gh.PullRequestReview, len(rr.PullRequests))
+		ciResults := make([]github.CIStatus, len(rr.PullRequests))
 		reviewErrs := make([]error, len(rr.PullRequests))
 
 		var reviewWg sync.WaitGroup
 		for i, pr := range rr.PullRequests {
 			reviewWg.Add(1)
-			go func(idx int, prNumber int) {
+			go func(idx int, pr *gh.PullRequest) {
 				defer reviewWg.Done()
-				reviewResults[idx], reviewErrs[idx] = client.ListReviews(ctx, owner, repo, prNumber)
-				if progress != nil {
-					progress.Increment(repoIndex)
+				defer func() {
+					if progress != nil {
+						progress.Increment(repoIndex)
+					}
+				}()
+				reviewResults[idx], reviewErrs[idx] = client.ListReviews(ctx, owner, repo, pr.GetNumber())
+				if reviewErrs[idx] != nil {
+					return
+				}
+				if sha := pr.GetHead().GetSHA(); sha != "" {
+					ciResults[idx], reviewErrs[idx] = client.GetCIStatus(ctx, owner, repo, sha)
 				}
-			}(i, pr.GetNumber())
+			}(i, pr)
 		}
 		reviewWg.Wait()
 
-		// 检查错误并填充 Reviews map
+		// 检查错误并填充 Reviews 和 CI map
 		for i, pr := range rr.PullRequests {
 			if reviewErrs[i] != nil {
-				return nil, fmt.Errorf("listing reviews for %s/%s#%d: %w", owner, repo, pr.GetNumber(), reviewErrs[i])
+				return nil, fmt.Errorf("fetching reviews and CI status for %s/%s#%d: %w", owner, repo, pr.GetNumber(), reviewErrs[i])
 			}
 			if len(reviewResults[i]) > 0 {
 				rr.Reviews[pr.GetNumber()] = reviewResults[i]
 			}
+			if ciResults[i].State != "" {
+				rr.CI[pr.GetNumber()] = ciResults[i]
+			}
 		}
 	}
 
+	// 提交：按作者和时间过滤，再排除已被列出的 PR 覆盖的提交
+	var commits []*gh.RepositoryCommit
+	for _, c := range rawCommits {
+		if commitDate(c).After(until) {
+			continue
+		}
+		// 合并提交不是直接的工作内容
+		if len(c.Parents) > 1 {
+			continue
+		}
+		if !matchUser(c.GetAuthor().GetLogin()) {
+			continue
+		}
+		commits = append(commits, c)
+	}
+	commits, err := dedupeCommits(ctx, client, owner, repo, commits, rr.PullRequests)
+	if err != nil {
+		return nil, fmt.Errorf("deduplicating commits for %s/%s: %w", owner, repo, err)
+	}
+	rr.Commits = commits
+
 	// 完成步：确保进度条到达 100%
 	if progress != nil {
 		progress.Increment(repoIndex)
@@ -276,11 +789,200 @@ func collectRepo(ctx context.Context, client *github.Client, owner, repo string,
 	return rr, nil
 }
 
-// prHasActivitySince 判断 PR 在指定时间之后是否有实际活动。
-// 仅当 PR 在时间范围内创建、合并、关闭，或仍处于 open 状态时返回 true。
+// closingKeywordPattern 匹配 PR 描述中关闭同仓库 Issue 的关键字，如 "fixes #12"、"Closes: #12"。
+var closingKeywordPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+#(\d+)\b`)
+
+// parseLinkedIssues 从 PR 描述的关闭关键字中解析关联的同仓库 Issue。
+// Issue 的标题、链接和状态取自 issues（仓库在获取时间范围内更新过的 Issue），不在其中的 Issue 只有编号、链接，状态为空。
+func parseLinkedIssues(pr *gh.PullRequest, issues []*gh.Issue) []github.LinkedIssue {
+	byNumber := make(map[int]*gh.Issue, len(issues))
+	for _, issue := range issues {
+		if !issue.IsPullRequest() {
+			byNumber[issue.GetNumber()] = issue
+		}
+	}
+
+	var linked []github.LinkedIssue
+	seen := make(map[int]bool)
+	for _, m := range closingKeywordPattern.FindAllStringSubmatch(pr.GetBody(), -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n == pr.GetNumber() || seen[n] {
+			continue
+		}
+		seen[n] = true
+		if issue, ok := byNumber[n]; ok {
+			linked = append(linked, github.LinkedIssue{
+				Number: n,
+				Title:  issue.GetTitle(),
+				URL:    issue.GetHTMLURL(),
+				State:  issue.GetState(),
+			})
+			continue
+		}
+		linked = append(linked, github.LinkedIssue{
+			Number: n,
+			URL:    strings.Replace(pr.GetHTMLURL(), fmt.Sprintf("/pull/%d", pr.GetNumber()), fmt.Sprintf("/issues/%d", n), 1),
+		})
+	}
+	return linked
+}
+
+// listCommits 获取默认分支和 q.commitBranches 匹配的分支上自 q.since 以来的提交，按 SHA 去重。
+// 只获取最新提交不早于 q.since 的分支（更早的分支在时间范围内不可能有新提交）；
+// 没有配置分支模式时直接获取默认分支，不查询分支列表。
+func listCommits(ctx context.Context, client *github.Client, owner, repo string, q repoQuery) ([]*gh.RepositoryCommit, error) {
+	if len(q.commitBranches) == 0 {
+		return client.ListCommits(ctx, owner, repo, q.since, q.commitAuthor)
+	}
+
+	branches, err := client.ListBranches(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []*gh.RepositoryCommit
+	seen := make(map[string]bool)
+	for _, b := range branches {
+		if b.CommittedAt.Before(q.since) || (!b.Default && !matchBranch(b.Name, q.commitBranches)) {
+			continue
+		}
+		commits, err := client.ListBranchCommits(ctx, owner, repo, b.Name, q.since, q.commitAuthor)
+		if err != nil {
+			return nil, fmt.Errorf("branch %s: %w", b.Name, err)
+		}
+		for _, c := range commits {
+			if !seen[c.GetSHA()] {
+				seen[c.GetSHA()] = true
+				all = append(all, c)
+			}
+		}
+	}
+	return all, nil
+}
+
+// matchBranch 判断分支名是否匹配任一 glob 模式（path.Match 语义，"*" 不匹配 "/"）。
+func matchBranch(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// prRefPattern 匹配提交信息中对 PR 的引用：squash 合并的 "(#123)" 和合并提交的 "Merge pull request #123"。
+var prRefPattern = regexp.MustCompile(`\(#(\d+)\)|Merge pull request #(\d+)`)
+
+// dedupeCommits 排除已被 prs 覆盖的提交，避免同一工作既作为 PR 又作为提交出现。
+//
+// 先用 uncoveredCommits 按 SHA 和提交信息排除（不需要请求）；仍无法判断的提交再通过 GraphQL 批量查询
+// 包含该提交的 PR（覆盖 merge 合并中 PR 分支上的中间提交），每次请求最多查询 50 个提交。
+func dedupeCommits(ctx context.Context, client *github.Client, owner, repo string, commits []*gh.RepositoryCommit, prs []*gh.PullRequest) ([]*gh.RepositoryCommit, error) {
+	if len(commits) == 0 || len(prs) == 0 {
+		return commits, nil
+	}
+
+	candidates := uncoveredCommits(commits, prs)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	shas := make([]string, len(candidates))
+	for i, c := range candidates {
+		shas[i] = c.GetSHA()
+	}
+	associated, err := client.ListCommitsPullRequests(ctx, owner, repo, shas)
+	if err != nil {
+		return nil, err
+	}
+	return excludeAssociatedCommits(candidates, associated, prs), nil
+}
+
+// uncoveredCommits 返回不能直接确定被 prs 覆盖的提交：提交 SHA 是 PR 的合并提交或 head 提交，
+// 或提交信息引用了 PR 编号（如 squash 合并的 "(#12)"）时视为已覆盖。
+func uncoveredCommits(commits []*gh.RepositoryCommit, prs []*gh.PullRequest) []*gh.RepositoryCommit {
+	prNumbers := prNumberSet(prs)
+	prSHAs := make(map[string]bool, len(prs)*2)
+	for _, pr := range prs {
+		if sha := pr.GetMergeCommitSHA(); sha != "" {
+			prSHAs[sha] = true
+		}
+		if sha := pr.GetHead().GetSHA(); sha != "" {
+			prSHAs[sha] = true
+		}
+	}
+
+	var kept []*gh.RepositoryCommit
+	for _, c := range commits {
+		if prSHAs[c.GetSHA()] || referencesPR(c.GetCommit().GetMessage(), prNumbers) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// excludeAssociatedCommits 排除关联 PR（associated，以提交 SHA 为键）中包含 prs 之一的提交。
+func excludeAssociatedCommits(commits []*gh.RepositoryCommit, associated map[string][]int, prs []*gh.PullRequest) []*gh.RepositoryCommit {
+	prNumbers := prNumberSet(prs)
+	var kept []*gh.RepositoryCommit
+	for _, c := range commits {
+		covered := false
+		for _, n := range associated[c.GetSHA()] {
+			if prNumbers[n] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// prNumberSet 返回 prs 的编号集合。
+func prNumberSet(prs []*gh.PullRequest) map[int]bool {
+	numbers := make(map[int]bool, len(prs))
+	for _, pr := range prs {
+		numbers[pr.GetNumber()] = true
+	}
+	return numbers
+}
+
+// referencesPR 判断提交信息是否引用了 prNumbers 中的 PR。
+func referencesPR(message string, prNumbers map[int]bool) bool {
+	for _, m := range prRefPattern.FindAllStringSubmatch(message, -1) {
+		ref := m[1]
+		if ref == "" {
+			ref = m[2]
+		}
+		if n, err := strconv.Atoi(ref); err == nil && prNumbers[n] {
+			return true
+		}
+	}
+	return false
+}
+
+// commitDate 返回提交进入仓库的时间（提交者时间）。
+func commitDate(c *gh.RepositoryCommit) time.Time {
+	return c.GetCommit().GetCommitter().GetDate().Time
+}
+
+// isEmptyRepoError 判断错误是否为空仓库（没有任何提交）导致的 409 Conflict。
+func isEmptyRepoError(err error) bool {
+	var ghErr *gh.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusConflict
+}
+
+// prHasActivitySince 判断 PR 在 [since, until] 时间范围内是否有实际活动。
+// 仅当 PR 在时间范围内创建、合并、关闭，或在 until 时仍处于 open 状态时返回 true。
 // 避免因 GitHub 自动更新 UpdatedAt（如 bot 评论、标签变更）导致旧 PR 被误收录。
-func prHasActivitySince(pr *gh.PullRequest, since time.Time) bool {
-	if pr.GetState() == "open" {
+func prHasActivitySince(pr *gh.PullRequest, since, until time.Time) bool {
+	if pr.GetCreatedAt().After(until) {
+		return false
+	}
+	state := prStateAt(pr, until)
+	if state == "open" || state == "draft" {
 		return true
 	}
 	if !pr.GetCreatedAt().Before(since) {
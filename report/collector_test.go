@@ -0,0 +1,158 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v69/github"
+)
+
+// testCommit 构造一个带 SHA 和提交信息的提交。
+func testCommit(sha, message string) *gh.RepositoryCommit {
+	return &gh.RepositoryCommit{SHA: gh.Ptr(sha), Commit: &gh.Commit{Message: gh.Ptr(message)}}
+}
+
+// commitSHAs 返回提交的 SHA，以逗号连接。
+func commitSHAs(commits []*gh.RepositoryCommit) string {
+	shas := make([]string, len(commits))
+	for i, c := range commits {
+		shas[i] = c.GetSHA()
+	}
+	return strings.Join(shas, ",")
+}
+
+func TestDedupeCommitsRules(t *testing.T) {
+	prs := []*gh.PullRequest{
+		{Number: gh.Ptr(12), MergeCommitSHA: gh.Ptr("merge12"), Head: &gh.PullRequestBranch{SHA: gh.Ptr("head12")}},
+		{Number: gh.Ptr(34), Head: &gh.PullRequestBranch{SHA: gh.Ptr("head34")}},
+	}
+	commits := []*gh.RepositoryCommit{
+		testCommit("merge12", "Fix login"),
+		testCommit("head34", "WIP"),
+		testCommit("squash", "Fix login (#12)"),
+		testCommit("mergepr", "Merge pull request #34 from acme/feature"),
+		testCommit("other", "Fix typo (#99)"),
+		testCommit("branch", "Add tests"),
+		testCommit("direct", "Bump version"),
+	}
+
+	candidates := uncoveredCommits(commits, prs)
+	if got, want := commitSHAs(candidates), "other,branch,direct"; got != want {
+		t.Fatalf("uncoveredCommits() = %s, want %s", got, want)
+	}
+
+	// branch 是 PR #34 分支上的中间提交；other 只关联到未列出的 PR #99
+	associated := map[string][]int{
+		"branch": {34},
+		"other":  {99},
+	}
+	if got, want := commitSHAs(excludeAssociatedCommits(candidates, associated, prs)), "other,direct"; got != want {
+		t.Errorf("excludeAssociatedCommits() = %s, want %s", got, want)
+	}
+}
+
+func TestUncoveredCommitsWithoutPRs(t *testing.T) {
+	commits := []*gh.RepositoryCommit{testCommit("a", "Fix (#1)"), testCommit("b", "Merge pull request #2")}
+	if got := commitSHAs(uncoveredCommits(commits, nil)); got != "a,b" {
+		t.Errorf("uncoveredCommits() = %s, want a,b", got)
+	}
+}
+
+// reviewRequested 构造一个 review_requested 事件，reviewer 或 team 二选一。
+func reviewRequested(reviewer, team string, at time.Time) *gh.Timeline {
+	e := &gh.Timeline{Event: gh.Ptr("review_requested"), CreatedAt: &gh.Timestamp{Time: at}}
+	if reviewer != "" {
+		e.Reviewer = &gh.User{Login: gh.Ptr(reviewer)}
+	}
+	if team != "" {
+		e.RequestedTeam = &gh.Team{Slug: gh.Ptr(team)}
+	}
+	return e
+}
+
+func TestLastReviewRequest(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+	inBackend := func(slug string) bool { return slug == "backend" }
+
+	tests := []struct {
+		name     string
+		events   []*gh.Timeline
+		wantAt   time.Time
+		wantTeam string
+	}{
+		{
+			name:   "direct request wins",
+			events: []*gh.Timeline{reviewRequested("Alice", "", day(1)), reviewRequested("", "backend", day(3))},
+			wantAt: day(1),
+		},
+		{
+			name:   "latest direct request",
+			events: []*gh.Timeline{reviewRequested("alice", "", day(1)), reviewRequested("bob", "", day(2)), reviewRequested("alice", "", day(4))},
+			wantAt: day(4),
+		},
+		{
+			name:     "member team",
+			events:   []*gh.Timeline{reviewRequested("", "backend", day(2)), reviewRequested("", "frontend", day(5))},
+			wantAt:   day(2),
+			wantTeam: "backend",
+		},
+		{
+			// 用户不属于的团队请求不计入
+			name:   "other team only",
+			events: []*gh.Timeline{reviewRequested("bob", "", day(1)), reviewRequested("", "frontend", day(5))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, team := lastReviewRequest(tt.events, "alice", inBackend)
+			if !at.Equal(tt.wantAt) || team != tt.wantTeam {
+				t.Errorf("lastReviewRequest() = %v, %q, want %v, %q", at, team, tt.wantAt, tt.wantTeam)
+			}
+		})
+	}
+}
+
+func TestIsBackfill(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	now := time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC) // 东八区 09:00
+	tests := []struct {
+		until time.Time
+		want  bool
+	}{
+		{until: now, want: false},
+		{until: time.Date(2026, 10, 16, 23, 59, 59, 0, shanghai), want: false},
+		{until: time.Date(2026, 10, 16, 0, 0, 0, 0, shanghai), want: false},
+		{until: time.Date(2026, 10, 15, 23, 59, 59, 0, shanghai), want: true},
+		{until: time.Date(2026, 9, 30, 23, 59, 59, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		if got := isBackfill(tt.until, now); got != tt.want {
+			t.Errorf("isBackfill(%v) = %v, want %v", tt.until, got, tt.want)
+		}
+	}
+}
+
+func TestRepoReportOwnsURL(t *testing.T) {
+	tests := []struct {
+		name string
+		rr   RepoReport
+		url  string
+		want bool
+	}{
+		{"same repo", RepoReport{Owner: "acme", Repo: "web"}, "https://github.com/acme/web/issues/1", true},
+		{"case insensitive", RepoReport{Owner: "Acme", Repo: "Web"}, "https://github.com/acme/web/pull/2", true},
+		{"repo name prefix", RepoReport{Owner: "acme", Repo: "web"}, "https://github.com/acme/web-admin/issues/1", false},
+		{"other host", RepoReport{Owner: "acme", Repo: "web"}, "https://ghe.example.com/acme/web/issues/1", false},
+		{"explicit host", RepoReport{Host: "ghe.example.com", Owner: "acme", Repo: "web"}, "https://ghe.example.com/acme/web/issues/1", true},
+		// 默认客户端指向 GitHub Enterprise Server 时，Host 为空但链接指向该主机
+		{"default enterprise host", RepoReport{Owner: "acme", Repo: "web", webHost: "ghe.example.com"}, "https://ghe.example.com/acme/web/issues/1", true},
+		{"default enterprise host rejects github.com", RepoReport{Owner: "acme", Repo: "web", webHost: "ghe.example.com"}, "https://github.com/acme/web/issues/1", false},
+		{"not a url", RepoReport{Owner: "acme", Repo: "web"}, "github.com/acme/web/issues/1", false},
+	}
+	for _, tt := range tests {
+		if got := tt.rr.ownsURL(tt.url); got != tt.want {
+			t.Errorf("%s: ownsURL(%q) = %v, want %v", tt.name, tt.url, got, tt.want)
+		}
+	}
+}
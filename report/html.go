@@ -0,0 +1,425 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miclle/gh-report/github"
+)
+
+// htmlPage 是 HTML 报告模板的数据。
+type htmlPage struct {
+	Title   string
+	User    string
+	Since   string
+	Until   string
+	Members []htmlMember // 团队模式下每个成员一节，非团队模式为空
+	Repos   []htmlRepo
+}
+
+// htmlMember 是团队成员在 HTML 报告中的数据：各类工作条目的数量以及工作和计划条目。
+type htmlMember struct {
+	User   string
+	Counts [6]int // PR、Issue、评论、Review、提交、计划
+	Work   []htmlMemberRow
+	Plan   []htmlMemberRow
+}
+
+// htmlMemberRow 是成员工作或计划表格中的一行，条目可能来自不同仓库。
+type htmlMemberRow struct {
+	Number string
+	Title  string
+	URL    string
+	State  string
+	Repo   string // 仓库全名
+	Kind   string // 工作条目的类型（pr、issue 等）或计划条目的来源
+	Date   string
+}
+
+// htmlRepo 是单个仓库在 HTML 报告中的数据。
+type htmlRepo struct {
+	Name           string
+	Issues         []htmlRow
+	PullRequests   []htmlRow
+	IssueComments  []htmlRow
+	ReviewComments []htmlRow
+	Reviews        []htmlRow
+	Commits        []htmlRow
+	Boards         []htmlBoard
+}
+
+// htmlRow 是表格中的一行。
+type htmlRow struct {
+	Number string
+	Title  string
+	URL    string
+	State  string
+	User   string
+	Date   string
+	Extra  string
+	CI     string // PR 的 CI 状态（success、failure、pending）
+	CIInfo string // 失败的检查名称
+}
+
+// htmlBoard 是单个项目的迭代看板，按 Previous / Current / Next 分列。
+type htmlBoard struct {
+	Project string
+	Columns []htmlColumn
+}
+
+// htmlColumn 是看板中的一列（一个迭代）。
+type htmlColumn struct {
+	Category  string
+	Iteration string
+	Items     []htmlCard
+}
+
+// htmlCard 是看板中的一张卡片（一个项目工作项）。
+type htmlCard struct {
+	Number    int
+	Title     string
+	URL       string
+	Status    string
+	Assignees string
+}
+
+// PrintHTML 将完整的活动报告渲染为单个离线可用的 HTML 文件（内嵌 CSS，无外部依赖）。
+// 每个仓库一个可折叠分区，包含 Issues、PRs、评论、Review 以及迭代看板。
+func PrintHTML(w io.Writer, reports []RepoReport, since, until time.Time, user string, rt ReportType) error {
+	return htmlTemplate.Execute(w, buildHTMLPage(reports, since, until, user, rt))
+}
+
+// buildHTMLPage 构建 HTML 报告模板的数据。
+func buildHTMLPage(reports []RepoReport, since, until time.Time, user string, rt ReportType) htmlPage {
+	labels := labelsForType(rt)
+	page := htmlPage{
+		Title: "gh-report " + labels.reportName,
+		User:  displayUser(user),
+		Since: since.Format("2006-01-02"),
+		Until: until.Format("2006-01-02"),
+	}
+
+	for _, rr := range reports {
+		page.Repos = append(page.Repos, buildHTMLRepo(rr, until))
+	}
+	return page
+}
+
+// buildHTMLMember 将成员的工作和计划条目转换为 HTML 模板数据。
+func buildHTMLMember(s MemberSummary) htmlMember {
+	counts := countWorkItems(s.WorkItems)
+	hm := htmlMember{
+		User:   s.User,
+		Counts: [6]int{counts["pr"], counts["issue"], counts["comment"], counts["review"], counts["commit"], len(s.PlanItems)},
+	}
+	for _, item := range s.WorkItems {
+		number := "#" + strconv.Itoa(item.Number)
+		if item.Type == "commit" {
+			number = item.SHA
+		}
+		hm.Work = append(hm.Work, htmlMemberRow{
+			Number: number,
+			Title:  item.Title,
+			URL:    item.URL,
+			State:  item.State,
+			Repo:   item.Repo,
+			Kind:   item.Type,
+			Date:   item.Date,
+		})
+	}
+	for _, item := range s.PlanItems {
+		hm.Plan = append(hm.Plan, htmlMemberRow{
+			Number: "#" + strconv.Itoa(item.Number),
+			Title:  item.Title,
+			URL:    item.URL,
+			State:  item.Status,
+			Repo:   item.Repo,
+			Kind:   item.Source,
+		})
+	}
+	return hm
+}
+
+// buildHTMLRepo 将 RepoReport 转换为 HTML 模板数据，日期按 now 所在时区格式化。
+func buildHTMLRepo(rr RepoReport, now time.Time) htmlRepo {
+	fullRepo := rr.FullName()
+	hr := htmlRepo{Name: fullRepo}
+	loc := now.Location()
+
+	for _, issue := range rr.Issues {
+		hr.Issues = append(hr.Issues, htmlRow{
+			Number: strconv.Itoa(issue.GetNumber()),
+			Title:  issue.GetTitle(),
+			URL:    issue.GetHTMLURL(),
+			State:  issue.GetState(),
+			User:   issue.GetUser().GetLogin(),
+			Date:   formatDate(issue.GetUpdatedAt().Time, loc),
+		})
+	}
+
+	for _, pr := range rr.PullRequests {
+		hr.PullRequests = append(hr.PullRequests, htmlRow{
+			Number: strconv.Itoa(pr.GetNumber()),
+			Title:  pr.GetTitle(),
+			URL:    pr.GetHTMLURL(),
+			State:  prDisplayState(pr),
+			User:   pr.GetUser().GetLogin(),
+			Date:   formatDate(pr.GetUpdatedAt().Time, loc),
+			Extra:  buildReviewSummary(rr.Reviews[pr.GetNumber()]),
+			CI:     rr.CI[pr.GetNumber()].State,
+			CIInfo: strings.Join(rr.CI[pr.GetNumber()].Failing, ", "),
+		})
+
+		for _, r := range rr.Reviews[pr.GetNumber()] {
+			if r.GetState() == "PENDING" {
+				continue
+			}
+			date := ""
+			if r.SubmittedAt != nil {
+				date = formatDate(r.SubmittedAt.Time, loc)
+			}
+			hr.Reviews = append(hr.Reviews, htmlRow{
+				Number: strconv.Itoa(pr.GetNumber()),
+				Title:  pr.GetTitle(),
+				URL:    r.GetHTMLURL(),
+				State:  r.GetState(),
+				User:   r.GetUser().GetLogin(),
+				Date:   date,
+			})
+		}
+	}
+
+	for _, c := range rr.IssueComments {
+		hr.IssueComments = append(hr.IssueComments, htmlRow{
+			Number: extractNumber(c.GetIssueURL()),
+			Title:  truncate(strings.TrimSpace(c.GetBody()), 120),
+			URL:    c.GetHTMLURL(),
+			User:   c.GetUser().GetLogin(),
+			Date:   formatDate(c.GetCreatedAt().Time, loc),
+		})
+	}
+
+	for _, c := range rr.ReviewComments {
+		hr.ReviewComments = append(hr.ReviewComments, htmlRow{
+			Number: extractNumber(c.GetPullRequestURL()),
+			Title:  truncate(strings.TrimSpace(c.GetBody()), 120),
+			URL:    c.GetHTMLURL(),
+			User:   c.GetUser().GetLogin(),
+			Date:   formatDate(c.GetCreatedAt().Time, loc),
+			Extra:  c.GetPath(),
+		})
+	}
+
+	for _, c := range rr.Commits {
+		hr.Commits = append(hr.Commits, htmlRow{
+			Number: shortSHA(c.GetSHA()),
+			Title:  commitTitle(c),
+			URL:    c.GetHTMLURL(),
+			User:   c.GetAuthor().GetLogin(),
+			Date:   formatDate(commitDate(c), loc),
+		})
+	}
+
+	for _, project := range rr.Projects {
+		if board, ok := buildHTMLBoard(project, rr, now); ok {
+			hr.Boards = append(hr.Boards, board)
+		}
+	}
+
+	return hr
+}
+
+// buildHTMLBoard 构建单个项目的迭代看板，仅包含与当前仓库相关的工作项。
+// 项目中没有与当前仓库相关的工作项时返回 false。
+func buildHTMLBoard(project github.Project, rr RepoReport, now time.Time) (htmlBoard, bool) {
+	relevant := github.FindRelevantIterations(project.Iterations, now)
+	entries := []struct {
+		category  string
+		iteration *github.ProjectIteration
+	}{
+		{"Previous", relevant.Previous},
+		{"Current", relevant.Current},
+		{"Next", relevant.Next},
+	}
+
+	board := htmlBoard{Project: project.Title}
+	hasItems := false
+	for _, entry := range entries {
+		if entry.iteration == nil {
+			continue
+		}
+		col := htmlColumn{Category: entry.category, Iteration: entry.iteration.Title}
+		for _, item := range project.Items {
+			if item.Iteration != entry.iteration.Title || !rr.ownsURL(item.URL) {
+				continue
+			}
+			col.Items = append(col.Items, htmlCard{
+				Number:    item.Number,
+				Title:     item.Title,
+				URL:       item.URL,
+				Status:    item.Status,
+				Assignees: strings.Join(item.Assignees, ", "),
+			})
+		}
+		if len(col.Items) > 0 {
+			hasItems = true
+		}
+		board.Columns = append(board.Columns, col)
+	}
+	return board, hasItems
+}
+
+// htmlTemplate 是 HTML 报告模板。CSS 内嵌，便于作为邮件附件离线打开。
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"stateClass": func(state string) string {
+		return "state-" + strings.ToLower(state)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} {{.Since}} ~ {{.Until}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; margin: 0; padding: 24px; color: #1f2328; background: #f6f8fa; }
+  h1 { margin: 0 0 4px; font-size: 24px; }
+  .meta { color: #59636e; margin-bottom: 24px; }
+  details.repo { background: #fff; border: 1px solid #d1d9e0; border-radius: 8px; margin-bottom: 16px; }
+  details.repo > summary { cursor: pointer; padding: 12px 16px; font-size: 18px; font-weight: 600; }
+  details.section { margin: 0 16px 12px; }
+  details.section > summary { cursor: pointer; font-weight: 600; padding: 6px 0; }
+  .count { color: #59636e; font-weight: normal; }
+  table { border-collapse: collapse; width: 100%; font-size: 14px; }
+  th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eaeef2; vertical-align: top; }
+  th { background: #f6f8fa; }
+  a { color: #0969da; text-decoration: none; }
+  a:hover { text-decoration: underline; }
+  .badge { display: inline-block; padding: 0 8px; border-radius: 12px; font-size: 12px; line-height: 20px; background: #eaeef2; white-space: nowrap; }
+  .state-open, .state-approved { background: #dafbe1; color: #1a7f37; }
+  .state-merged { background: #fbefff; color: #8250df; }
+  .state-closed, .state-changes_requested { background: #ffebe9; color: #cf222e; }
+  .state-draft, .state-commented { background: #eaeef2; color: #59636e; }
+  .state-success { background: #dafbe1; color: #1a7f37; }
+  .state-failure { background: #ffebe9; color: #cf222e; }
+  .state-pending { background: #fff8c5; color: #9a6700; }
+  .board { display: flex; gap: 12px; overflow-x: auto; padding-bottom: 8px; }
+  .column { flex: 1 1 0; min-width: 220px; background: #f6f8fa; border-radius: 6px; padding: 8px; }
+  .column h4 { margin: 0 0 8px; font-size: 14px; }
+  .card { background: #fff; border: 1px solid #d1d9e0; border-radius: 6px; padding: 8px; margin-bottom: 8px; font-size: 13px; }
+  .card .sub { color: #59636e; margin-top: 4px; }
+  .empty { color: #59636e; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">{{.Since}} ~ {{.Until}}{{if .User}} · {{.User}}{{end}}</div>
+{{if .Members}}
+<details class="repo" open>
+<summary>团队概览</summary>
+<div class="section">
+<table>
+<tr><th>成员</th><th>PR</th><th>Issue</th><th>评论</th><th>Review</th><th>提交</th><th>计划</th></tr>
+{{range .Members}}<tr><td>@{{.User}}</td>{{range .Counts}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+</div>
+</details>
+{{range .Members}}
+<details class="repo">
+<summary>@{{.User}}</summary>
+<details class="section" open>
+<summary>工作 <span class="count">({{len .Work}})</span></summary>
+{{if .Work}}<table>
+<tr><th>#</th><th>标题</th><th>类型</th><th>状态</th><th>仓库</th><th>日期</th></tr>
+{{range .Work}}<tr><td>{{.Number}}</td><td><a href="{{.URL}}">{{.Title}}</a></td><td>{{.Kind}}</td><td>{{if .State}}<span class="badge {{stateClass .State}}">{{.State}}</span>{{end}}</td><td>{{.Repo}}</td><td>{{.Date}}</td></tr>
+{{end}}</table>{{else}}<p class="empty">无工作数据</p>{{end}}
+</details>
+<details class="section" open>
+<summary>计划 <span class="count">({{len .Plan}})</span></summary>
+{{if .Plan}}<table>
+<tr><th>#</th><th>标题</th><th>来源</th><th>状态</th><th>仓库</th></tr>
+{{range .Plan}}<tr><td>{{.Number}}</td><td><a href="{{.URL}}">{{.Title}}</a></td><td>{{.Kind}}</td><td>{{if .State}}<span class="badge">{{.State}}</span>{{end}}</td><td>{{.Repo}}</td></tr>
+{{end}}</table>{{else}}<p class="empty">无计划数据</p>{{end}}
+</details>
+</details>
+{{end}}
+{{end}}
+{{range .Repos}}
+<details class="repo" open>
+<summary>{{.Name}}</summary>
+{{if .Issues}}
+<details class="section" open>
+<summary>Issues <span class="count">({{len .Issues}})</span></summary>
+<table>
+<tr><th>#</th><th>标题</th><th>状态</th><th>用户</th><th>日期</th></tr>
+{{range .Issues}}<tr><td>{{.Number}}</td><td><a href="{{.URL}}">{{.Title}}</a></td><td><span class="badge {{stateClass .State}}">{{.State}}</span></td><td>@{{.User}}</td><td>{{.Date}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{if .PullRequests}}
+<details class="section" open>
+<summary>Pull Requests <span class="count">({{len .PullRequests}})</span></summary>
+<table>
+<tr><th>#</th><th>标题</th><th>状态</th><th>用户</th><th>日期</th><th>Reviews</th><th>CI</th></tr>
+{{range .PullRequests}}<tr><td>{{.Number}}</td><td><a href="{{.URL}}">{{.Title}}</a></td><td><span class="badge {{stateClass .State}}">{{.State}}</span></td><td>@{{.User}}</td><td>{{.Date}}</td><td>{{.Extra}}</td><td>{{if .CI}}<span class="badge {{stateClass .CI}}"{{if .CIInfo}} title="{{.CIInfo}}"{{end}}>{{.CI}}</span>{{end}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{if .IssueComments}}
+<details class="section">
+<summary>Issue Comments <span class="count">({{len .IssueComments}})</span></summary>
+<table>
+<tr><th>#</th><th>用户</th><th>日期</th><th>内容</th></tr>
+{{range .IssueComments}}<tr><td>{{.Number}}</td><td>@{{.User}}</td><td>{{.Date}}</td><td><a href="{{.URL}}">{{.Title}}</a></td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{if .ReviewComments}}
+<details class="section">
+<summary>Review Comments <span class="count">({{len .ReviewComments}})</span></summary>
+<table>
+<tr><th>PR</th><th>用户</th><th>日期</th><th>文件</th><th>内容</th></tr>
+{{range .ReviewComments}}<tr><td>{{.Number}}</td><td>@{{.User}}</td><td>{{.Date}}</td><td>{{.Extra}}</td><td><a href="{{.URL}}">{{.Title}}</a></td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{if .Reviews}}
+<details class="section">
+<summary>Reviews <span class="count">({{len .Reviews}})</span></summary>
+<table>
+<tr><th>PR</th><th>标题</th><th>Reviewer</th><th>结果</th><th>日期</th></tr>
+{{range .Reviews}}<tr><td>{{.Number}}</td><td><a href="{{.URL}}">{{.Title}}</a></td><td>@{{.User}}</td><td><span class="badge {{stateClass .State}}">{{.State}}</span></td><td>{{.Date}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{if .Commits}}
+<details class="section">
+<summary>Commits <span class="count">({{len .Commits}})</span></summary>
+<table>
+<tr><th>SHA</th><th>提交信息</th><th>作者</th><th>日期</th></tr>
+{{range .Commits}}<tr><td><code>{{.Number}}</code></td><td><a href="{{.URL}}">{{.Title}}</a></td><td>{{if .User}}@{{.User}}{{end}}</td><td>{{.Date}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}
+{{range .Boards}}
+<details class="section" open>
+<summary>迭代看板 · {{.Project}}</summary>
+<div class="board">
+{{range .Columns}}<div class="column">
+<h4>{{.Category}} · {{.Iteration}} <span class="count">({{len .Items}})</span></h4>
+{{range .Items}}<div class="card"><a href="{{.URL}}">#{{.Number}} {{.Title}}</a>
+<div class="sub">{{if .Status}}<span class="badge">{{.Status}}</span> {{end}}{{.Assignees}}</div></div>
+{{else}}<div class="empty">无工作项</div>
+{{end}}</div>
+{{end}}</div>
+</details>
+{{end}}
+</details>
+{{else}}
+<p class="empty">无活动数据</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,292 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miclle/gh-report/github"
+)
+
+// extractItems 按报告类型提取工作和计划条目：迭代报告按迭代看板提取，其余报告类型按活动提取。
+func extractItems(reports []RepoReport, user string, since, until time.Time, rt ReportType) ([]WorkItem, []PlanItem) {
+	if rt == ReportIteration {
+		return extractIterationWorkItems(reports, user, since, until), extractIterationPlanItems(reports, user, until)
+	}
+	return extractWorkItems(reports, user, since, until), extractPlanItems(reports, user, until)
+}
+
+// FindIteration 在仓库所有者（以及 owners 中额外指定的默认主机上的组织或用户）的 Projects v2
+// 和 opts.Projects 指定的项目中查找迭代，用于确定迭代报告的时间范围。
+// title 非空时按标题查找（不区分大小写），否则查找 now 所在的当前迭代。多个项目都有匹配的迭代时，
+// 使用按仓库顺序第一个项目中的迭代，可通过 ProjectOptions 的 Include 和 IterationField 缩小范围。
+func FindIteration(ctx context.Context, client *github.Client, opts Options, owners []string, title string, now time.Time) (github.ProjectIteration, error) {
+	projectOpts := opts.ProjectOptions
+	projectOpts.SkipItems = true
+	projectOpts.Until = time.Time{}
+
+	type ownerKey struct {
+		host  string
+		owner string
+	}
+	var keys []ownerKey
+	clients := make(map[ownerKey]*github.Client)
+	addOwner := func(host, owner string) error {
+		c, err := opts.clientFor(client, host)
+		if err != nil {
+			return err
+		}
+		key := ownerKey{c.Host(), strings.ToLower(owner)}
+		if _, ok := clients[key]; !ok {
+			keys = append(keys, key)
+			clients[key] = c
+		}
+		return nil
+	}
+	for _, fullRepo := range opts.Repos {
+		host, owner, _, err := ParseRepo(fullRepo)
+		if err != nil {
+			return github.ProjectIteration{}, err
+		}
+		if err := addOwner(host, owner); err != nil {
+			return github.ProjectIteration{}, err
+		}
+	}
+	for _, owner := range owners {
+		if err := addOwner("", owner); err != nil {
+			return github.ProjectIteration{}, err
+		}
+	}
+
+	var projects []github.Project
+	for _, key := range keys {
+		list, err := clients[key].ListProjects(ctx, key.owner, projectOpts)
+		if err != nil {
+			return github.ProjectIteration{}, err
+		}
+		projects = append(projects, list...)
+	}
+	for _, s := range opts.Projects {
+		host, owner, number, err := ParseProject(s)
+		if err != nil {
+			return github.ProjectIteration{}, err
+		}
+		c, err := opts.clientFor(client, host)
+		if err != nil {
+			return github.ProjectIteration{}, err
+		}
+		p, err := c.GetProject(ctx, owner, number, projectOpts)
+		if err != nil {
+			return github.ProjectIteration{}, err
+		}
+		projects = append(projects, p)
+	}
+
+	for _, p := range projects {
+		for _, iter := range p.Iterations {
+			if title != "" && strings.EqualFold(strings.TrimSpace(title), iter.Title) {
+				return iter, nil
+			}
+			if title == "" && github.ClassifyIteration(iter, now) == github.IterationCurrent {
+				return iter, nil
+			}
+		}
+	}
+	if title != "" {
+		return github.ProjectIteration{}, fmt.Errorf("no iteration titled %q found in %d projects", title, len(projects))
+	}
+	return github.ProjectIteration{}, fmt.Errorf("no current iteration found in %d projects", len(projects))
+}
+
+// extractIterationWorkItems 提取迭代报告的工作条目：until 所在迭代中，于 [since, until] 内完成的工作项。
+//
+// 按 Issue / PR 的关闭（合并）时间判断（优先取报告数据中的时间，否则取项目数据中的时间）；
+// 没有关闭时间时（Issue / PR 未关闭但 Status 为 Done），只能以项目中当前的状态为准。
+func extractIterationWorkItems(reports []RepoReport, user string, since, until time.Time) []WorkItem {
+	user = filterLogin(user)
+	var items []WorkItem
+	seen := make(map[string]bool)
+
+	for _, rr := range reports {
+		fullRepo := rr.FullName()
+		closed := closeTimes(rr)
+		for _, project := range rr.Projects {
+			current := github.FindRelevantIterations(project.Iterations, until).Current
+			if current == nil {
+				continue
+			}
+			for _, item := range project.Items {
+				if item.Iteration != current.Title || !iterationItemMatches(item, rr, user) {
+					continue
+				}
+				closedAt := itemCloseTime(item, closed)
+				if !closedAt.IsZero() {
+					if closedAt.Before(since) || closedAt.After(until) {
+						continue
+					}
+				} else if !projectItemDone(item) {
+					continue
+				}
+				key := fmt.Sprintf("%s#%d", fullRepo, item.Number)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				state := strings.ToLower(item.State)
+				if state == "open" {
+					state = "done" // 项目中已完成，但 Issue / PR 尚未关闭
+				}
+				date := ""
+				if !closedAt.IsZero() {
+					date = formatDate(closedAt, until.Location())
+				}
+				items = append(items, WorkItem{
+					Type:   projectItemKind(item),
+					Repo:   fullRepo,
+					Number: item.Number,
+					Title:  item.Title,
+					State:  state,
+					URL:    item.URL,
+					Date:   date,
+				})
+			}
+		}
+	}
+
+	return items
+}
+
+// extractIterationPlanItems 提取迭代报告的计划条目。
+// 先列出上一迭代和本迭代（until 所在迭代）中截至 until 仍未完成的顺延工作项（carry_over），
+// 再列出下一迭代中未完成的工作项（next_iteration）。
+func extractIterationPlanItems(reports []RepoReport, user string, until time.Time) []PlanItem {
+	user = filterLogin(user)
+	var carryOver, next []PlanItem
+	seen := make(map[string]bool)
+
+	for _, rr := range reports {
+		fullRepo := rr.FullName()
+		closed := closeTimes(rr)
+		for _, project := range rr.Projects {
+			relevant := github.FindRelevantIterations(project.Iterations, until)
+			for _, item := range project.Items {
+				if !iterationItemMatches(item, rr, user) {
+					continue
+				}
+				key := fmt.Sprintf("%s#%d", fullRepo, item.Number)
+				if seen[key] {
+					continue
+				}
+				plan := PlanItem{
+					Repo:   fullRepo,
+					Number: item.Number,
+					Title:  item.Title,
+					URL:    item.URL,
+					Status: item.Status,
+					Fields: projectFieldsText(item.Fields),
+				}
+				switch {
+				case isIteration(relevant.Previous, item.Iteration) || isIteration(relevant.Current, item.Iteration):
+					if iterationItemDone(item, itemCloseTime(item, closed), until) {
+						continue
+					}
+					plan.Source = "carry_over"
+					plan.From = item.Iteration
+					carryOver = append(carryOver, plan)
+				case isIteration(relevant.Next, item.Iteration):
+					if iterationItemDone(item, itemCloseTime(item, closed), until) {
+						continue
+					}
+					plan.Source = "next_iteration"
+					next = append(next, plan)
+				default:
+					continue
+				}
+				seen[key] = true
+			}
+		}
+	}
+
+	return append(carryOver, next...)
+}
+
+// iterationItemMatches 判断工作项是否属于仓库，且在指定用户时分配给该用户。
+func iterationItemMatches(item github.ProjectItem, rr RepoReport, user string) bool {
+	if !rr.ownsURL(item.URL) {
+		return false
+	}
+	return user == "" || containsString(item.Assignees, user)
+}
+
+// isIteration 判断 title 是否为指定迭代的标题，iter 为 nil 时返回 false。
+func isIteration(iter *github.ProjectIteration, title string) bool {
+	return iter != nil && iter.Title == title
+}
+
+// projectItemDone 判断项目工作项是否已完成：Status 为 Done，或 Issue / PR 已关闭、已合并。
+func projectItemDone(item github.ProjectItem) bool {
+	upperState := strings.ToUpper(item.State)
+	return strings.EqualFold(item.Status, "Done") || upperState == "CLOSED" || upperState == "MERGED"
+}
+
+// iterationItemDone 判断工作项截至 until 是否已完成：有关闭（合并）时间时按该时间判断，
+// 否则以项目中当前的状态为准（Status 为 Done）。
+func iterationItemDone(item github.ProjectItem, closedAt, until time.Time) bool {
+	if !closedAt.IsZero() {
+		return !closedAt.After(until)
+	}
+	return projectItemDone(item)
+}
+
+// itemCloseTime 返回工作项的关闭（合并）时间：优先取报告数据中的时间，否则取项目数据中的时间。未关闭时返回零值。
+func itemCloseTime(item github.ProjectItem, closed map[int]time.Time) time.Time {
+	if t := closed[item.Number]; !t.IsZero() {
+		return t
+	}
+	return item.ClosedAt
+}
+
+// projectItemKind 将项目工作项的类型转换为工作条目类型（"pr" 或 "issue"）。
+func projectItemKind(item github.ProjectItem) string {
+	switch strings.ToUpper(strings.ReplaceAll(item.Type, "_", "")) {
+	case "PULLREQUEST":
+		return "pr"
+	default:
+		return "issue"
+	}
+}
+
+// closeTimes 返回报告数据中 Issue 和 PR 的关闭时间（PR 优先取合并时间），以编号为键。
+// 未关闭的 Issue / PR 对应零值。
+func closeTimes(rr RepoReport) map[int]time.Time {
+	times := make(map[int]time.Time, len(rr.Issues)+len(rr.PullRequests))
+	for _, issue := range rr.Issues {
+		var t time.Time
+		if issue.ClosedAt != nil {
+			t = issue.ClosedAt.Time
+		}
+		times[issue.GetNumber()] = t
+	}
+	for _, pr := range rr.PullRequests {
+		var t time.Time
+		switch {
+		case pr.MergedAt != nil:
+			t = pr.MergedAt.Time
+		case pr.ClosedAt != nil:
+			t = pr.ClosedAt.Time
+		}
+		times[pr.GetNumber()] = t
+	}
+	return times
+}
+
+// IterationWindow 返回迭代的起止时间（按 loc 解释）：起点为开始日期零点，终点为最后一天的最后一刻。
+func IterationWindow(iter github.ProjectIteration, loc *time.Location) (since, until time.Time, err error) {
+	start, err := time.ParseInLocation("2006-01-02", iter.StartDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q for iteration %q", iter.StartDate, iter.Title)
+	}
+	return start, start.AddDate(0, 0, iter.Duration).Add(-time.Nanosecond), nil
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miclle/gh-report/github"
+)
+
+// testIterationItem 构造 acme/web 中属于 iteration 的 Issue 工作项。
+func testIterationItem(number int, iteration, state, status string, closedAt time.Time) github.ProjectItem {
+	return github.ProjectItem{
+		Title:     fmt.Sprintf("Issue %d", number),
+		Number:    number,
+		URL:       fmt.Sprintf("https://github.com/acme/web/issues/%d", number),
+		State:     state,
+		ClosedAt:  closedAt,
+		Type:      "Issue",
+		Iteration: iteration,
+		Status:    status,
+	}
+}
+
+// TestIterationItemsUseCloseTime 回溯已结束的迭代时，按关闭时间而不是项目中当前的状态判断是否在迭代内完成。
+func TestIterationItemsUseCloseTime(t *testing.T) {
+	at := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 10, 0, 0, 0, time.UTC) }
+	since := time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 10, 11, 23, 59, 59, 0, time.UTC)
+
+	rr := RepoReport{Owner: "acme", Repo: "web", Projects: []github.Project{{
+		Title: "Board",
+		Iterations: []github.ProjectIteration{
+			{Title: "Sprint 1", StartDate: "2026-09-28", Duration: 14},
+			{Title: "Sprint 2", StartDate: "2026-10-12", Duration: 14},
+		},
+		Items: []github.ProjectItem{
+			testIterationItem(1, "Sprint 1", "CLOSED", "Done", at(10, 5)),  // 迭代内完成
+			testIterationItem(2, "Sprint 1", "CLOSED", "Done", at(10, 14)), // 迭代结束后才完成，顺延
+			testIterationItem(3, "Sprint 1", "OPEN", "Done", time.Time{}),  // 只有当前状态可参考
+			testIterationItem(4, "Sprint 1", "OPEN", "In Progress", time.Time{}),
+			testIterationItem(5, "Sprint 2", "CLOSED", "Done", at(10, 15)), // 截至 until 未完成
+			testIterationItem(6, "Sprint 2", "OPEN", "Done", time.Time{}),
+		},
+	}}}
+
+	var work []string
+	for _, item := range extractIterationWorkItems([]RepoReport{rr}, "", since, until) {
+		work = append(work, fmt.Sprintf("#%d %s %s", item.Number, item.State, item.Date))
+	}
+	if got, want := strings.Join(work, ", "), "#1 closed 2026-10-05, #3 done "; got != want {
+		t.Errorf("work items = %q, want %q", got, want)
+	}
+
+	var plan []string
+	for _, item := range extractIterationPlanItems([]RepoReport{rr}, "", until) {
+		plan = append(plan, fmt.Sprintf("#%d %s", item.Number, item.Source))
+	}
+	if got, want := strings.Join(plan, ", "), "#2 carry_over, #4 carry_over, #5 next_iteration"; got != want {
+		t.Errorf("plan items = %q, want %q", got, want)
+	}
+}
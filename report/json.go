@@ -0,0 +1,463 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	gh "github.com/google/go-github/v69/github"
+
+	"github.com/miclle/gh-report/github"
+)
+
+// JSONSchemaVersion 是 JSON 输出格式的版本号。
+// 仅在字段语义发生不兼容变化时递增；新增字段不改变版本号。
+const JSONSchemaVersion = 1
+
+// jsonReport 是 JSON 输出的顶层结构。
+type jsonReport struct {
+	SchemaVersion int        `json:"schema_version"`
+	ReportType    ReportType `json:"report_type"`
+	User          string     `json:"user"`
+	Since         time.Time  `json:"since"`
+	Until         time.Time  `json:"until"`
+	Repos         []jsonRepo `json:"repos"`
+	// Members 团队模式下每个成员的工作和计划条目，按成员顺序排列；非团队模式不输出
+	Members []jsonMember `json:"members,omitempty"`
+}
+
+// jsonMember 是团队成员的 JSON 输出结构，条目的提取规则与 summary 格式相同。
+type jsonMember struct {
+	User      string         `json:"user"`
+	WorkItems []jsonWorkItem `json:"work_items"`
+	PlanItems []jsonPlanItem `json:"plan_items"`
+}
+
+// jsonWorkItem 是工作条目的 JSON 输出结构。
+type jsonWorkItem struct {
+	Type         string `json:"type"` // pr、issue、comment、review、commit
+	Repo         string `json:"repo"`
+	Number       int    `json:"number,omitempty"`
+	SHA          string `json:"sha,omitempty"` // 提交的短 SHA（仅 commit）
+	Title        string `json:"title"`
+	State        string `json:"state,omitempty"`
+	URL          string `json:"url"`
+	Date         string `json:"date,omitempty"`
+	ReviewInfo   string `json:"review_info,omitempty"`
+	CI           string `json:"ci,omitempty"`
+	LinkedIssues []int  `json:"linked_issues,omitempty"`
+}
+
+// jsonPlanItem 是计划条目的 JSON 输出结构。
+type jsonPlanItem struct {
+	Source string `json:"source"` // open_pr、linked_issue、project_item、review_request、next_iteration、carry_over
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Status string `json:"status,omitempty"`
+	Fields string `json:"fields,omitempty"`
+	From   string `json:"from,omitempty"`
+	Age    string `json:"age,omitempty"`
+	PR     int    `json:"pr,omitempty"`
+}
+
+// jsonRepo 是单个仓库的 JSON 输出结构。
+type jsonRepo struct {
+	Host           string                  `json:"host,omitempty"` // 非默认主机（如 GitHub Enterprise Server）时输出
+	Owner          string                  `json:"owner"`
+	Repo           string                  `json:"repo"`
+	Issues         []jsonIssue             `json:"issues"`
+	PullRequests   []jsonPullRequest       `json:"pull_requests"`
+	IssueComments  []jsonIssueComment      `json:"issue_comments"`
+	ReviewComments []jsonReviewComment     `json:"review_comments"`
+	Reviews        map[string][]jsonReview `json:"reviews"` // 以 PR 编号（字符串）为键
+	Commits        []jsonCommit            `json:"commits"`
+	ReviewRequests []jsonReviewRequest     `json:"review_requests"`
+	Projects       []jsonProject           `json:"projects"`
+}
+
+// jsonIssue 是 Issue 的 JSON 输出结构。
+type jsonIssue struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	User      string     `json:"user"`
+	Assignees []string   `json:"assignees"`
+	Labels    []string   `json:"labels"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+// jsonPullRequest 是 Pull Request 的 JSON 输出结构。
+type jsonPullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // open、closed、merged、draft
+	Draft     bool       `json:"draft"`
+	User      string     `json:"user"`
+	Assignees []string   `json:"assignees"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	CI        *jsonCI    `json:"ci"`            // head 提交的 CI 状态，没有检查时为 null
+	Issues    []int      `json:"linked_issues"` // 关联的同仓库 Issue 编号
+}
+
+// jsonCI 是 CI 汇总状态的 JSON 输出结构。
+type jsonCI struct {
+	State   string   `json:"state"`   // success、failure、pending
+	Failing []string `json:"failing"` // 失败的检查名称
+}
+
+// jsonIssueComment 是 Issue 评论的 JSON 输出结构。
+type jsonIssueComment struct {
+	ID          int64     `json:"id"`
+	IssueNumber int       `json:"issue_number"`
+	User        string    `json:"user"`
+	Body        string    `json:"body"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// jsonReviewComment 是 PR Review 评论的 JSON 输出结构。
+type jsonReviewComment struct {
+	ID        int64     `json:"id"`
+	PRNumber  int       `json:"pr_number"`
+	User      string    `json:"user"`
+	Path      string    `json:"path"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// jsonCommit 是提交的 JSON 输出结构。
+type jsonCommit struct {
+	SHA     string    `json:"sha"`
+	Author  string    `json:"author"` // GitHub 用户名，提交作者未关联 GitHub 账号时为空
+	Message string    `json:"message"`
+	URL     string    `json:"url"`
+	Date    time.Time `json:"date"` // 提交者时间
+}
+
+// jsonReviewRequest 是等待用户 Review 的 PR 的 JSON 输出结构。
+type jsonReviewRequest struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author"`
+	Reviewer    string    `json:"reviewer"`
+	Team        string    `json:"team"` // 通过团队请求时的团队 slug，直接请求时为空
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// jsonReview 是 PR Review 的 JSON 输出结构。
+type jsonReview struct {
+	ID          int64      `json:"id"`
+	User        string     `json:"user"`
+	State       string     `json:"state"`
+	URL         string     `json:"url"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+}
+
+// jsonProject 是 Projects v2 项目的 JSON 输出结构。
+type jsonProject struct {
+	Title      string                    `json:"title"`
+	Number     int                       `json:"number"`
+	Iterations []github.ProjectIteration `json:"iterations"`
+	Items      []jsonProjectItem         `json:"items"`
+}
+
+// jsonProjectItem 是项目工作项的 JSON 输出结构。
+type jsonProjectItem struct {
+	Title     string   `json:"title"`
+	Number    int      `json:"number"`
+	URL       string   `json:"url"`
+	State     string   `json:"state"`
+	Type      string   `json:"type"`
+	Iteration string   `json:"iteration"`
+	Status    string   `json:"status"`
+	Assignees []string `json:"assignees"`
+	// Fields 其他自定义字段，以字段名为键；数字字段为数字，其余为字符串
+	Fields map[string]any `json:"fields"`
+}
+
+// PrintJSON 将完整的活动报告以带版本号的 JSON 格式写入 writer。
+// 输出包含报告时间范围、用户过滤条件和报告类型，便于下游程序直接解析。
+// 所有时间字段均转换到 until 所在时区。
+func PrintJSON(w io.Writer, reports []RepoReport, since, until time.Time, user string, rt ReportType) error {
+	return writeJSON(w, buildJSONReport(reports, since, until, user, rt))
+}
+
+// buildJSONReport 构建 JSON 输出的顶层结构。
+func buildJSONReport(reports []RepoReport, since, until time.Time, user string, rt ReportType) jsonReport {
+	out := jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		ReportType:    rt,
+		User:          user,
+		Since:         since,
+		Until:         until,
+		Repos:         make([]jsonRepo, 0, len(reports)),
+	}
+	for _, rr := range reports {
+		out.Repos = append(out.Repos, toJSONRepo(rr, until.Location()))
+	}
+	return out
+}
+
+// writeJSON 以缩进格式写入 JSON，不转义 HTML 字符。
+func writeJSON(w io.Writer, out jsonReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(out)
+}
+
+// toJSONRepo 将 RepoReport 转换为 JSON 输出结构，时间字段转换到 loc 时区。
+// 切片字段始终初始化为非 nil，保证输出中为 [] 而不是 null。
+func toJSONRepo(rr RepoReport, loc *time.Location) jsonRepo {
+	jr := jsonRepo{
+		Host:           rr.Host,
+		Owner:          rr.Owner,
+		Repo:           rr.Repo,
+		Issues:         make([]jsonIssue, 0, len(rr.Issues)),
+		PullRequests:   make([]jsonPullRequest, 0, len(rr.PullRequests)),
+		IssueComments:  make([]jsonIssueComment, 0, len(rr.IssueComments)),
+		ReviewComments: make([]jsonReviewComment, 0, len(rr.ReviewComments)),
+		Reviews:        make(map[string][]jsonReview, len(rr.Reviews)),
+		Commits:        make([]jsonCommit, 0, len(rr.Commits)),
+		ReviewRequests: make([]jsonReviewRequest, 0, len(rr.ReviewRequests)),
+		Projects:       make([]jsonProject, 0, len(rr.Projects)),
+	}
+
+	for _, issue := range rr.Issues {
+		labels := make([]string, 0, len(issue.Labels))
+		for _, l := range issue.Labels {
+			labels = append(labels, l.GetName())
+		}
+		jr.Issues = append(jr.Issues, jsonIssue{
+			Number:    issue.GetNumber(),
+			Title:     issue.GetTitle(),
+			State:     issue.GetState(),
+			User:      issue.GetUser().GetLogin(),
+			Assignees: userLogins(issue.Assignees),
+			Labels:    labels,
+			URL:       issue.GetHTMLURL(),
+			CreatedAt: issue.GetCreatedAt().In(loc),
+			UpdatedAt: issue.GetUpdatedAt().In(loc),
+			ClosedAt:  timestampPtr(issue.ClosedAt, loc),
+		})
+	}
+
+	for _, pr := range rr.PullRequests {
+		jr.PullRequests = append(jr.PullRequests, jsonPullRequest{
+			Number:    pr.GetNumber(),
+			Title:     pr.GetTitle(),
+			State:     prDisplayState(pr),
+			Draft:     pr.GetDraft(),
+			User:      pr.GetUser().GetLogin(),
+			Assignees: userLogins(pr.Assignees),
+			URL:       pr.GetHTMLURL(),
+			CreatedAt: pr.GetCreatedAt().In(loc),
+			UpdatedAt: pr.GetUpdatedAt().In(loc),
+			MergedAt:  timestampPtr(pr.MergedAt, loc),
+			ClosedAt:  timestampPtr(pr.ClosedAt, loc),
+			CI:        toJSONCI(rr.CI, pr.GetNumber()),
+			Issues:    linkedIssueNumbers(rr.LinkedIssues[pr.GetNumber()]),
+		})
+	}
+
+	for _, c := range rr.IssueComments {
+		n, _ := strconv.Atoi(extractNumber(c.GetIssueURL()))
+		jr.IssueComments = append(jr.IssueComments, jsonIssueComment{
+			ID:          c.GetID(),
+			IssueNumber: n,
+			User:        c.GetUser().GetLogin(),
+			Body:        c.GetBody(),
+			URL:         c.GetHTMLURL(),
+			CreatedAt:   c.GetCreatedAt().In(loc),
+			UpdatedAt:   c.GetUpdatedAt().In(loc),
+		})
+	}
+
+	for _, c := range rr.ReviewComments {
+		n, _ := strconv.Atoi(extractNumber(c.GetPullRequestURL()))
+		jr.ReviewComments = append(jr.ReviewComments, jsonReviewComment{
+			ID:        c.GetID(),
+			PRNumber:  n,
+			User:      c.GetUser().GetLogin(),
+			Path:      c.GetPath(),
+			Body:      c.GetBody(),
+			URL:       c.GetHTMLURL(),
+			CreatedAt: c.GetCreatedAt().In(loc),
+			UpdatedAt: c.GetUpdatedAt().In(loc),
+		})
+	}
+
+	for _, c := range rr.Commits {
+		jr.Commits = append(jr.Commits, jsonCommit{
+			SHA:     c.GetSHA(),
+			Author:  c.GetAuthor().GetLogin(),
+			Message: c.GetCommit().GetMessage(),
+			URL:     c.GetHTMLURL(),
+			Date:    commitDate(c).In(loc),
+		})
+	}
+
+	for _, req := range rr.ReviewRequests {
+		jr.ReviewRequests = append(jr.ReviewRequests, jsonReviewRequest{
+			Number:      req.Number,
+			Title:       req.Title,
+			URL:         req.URL,
+			Author:      req.Author,
+			Reviewer:    req.Reviewer,
+			Team:        req.Team,
+			RequestedAt: req.RequestedAt.In(loc),
+		})
+	}
+
+	for number, reviews := range rr.Reviews {
+		list := make([]jsonReview, 0, len(reviews))
+		for _, r := range reviews {
+			list = append(list, jsonReview{
+				ID:          r.GetID(),
+				User:        r.GetUser().GetLogin(),
+				State:       r.GetState(),
+				URL:         r.GetHTMLURL(),
+				SubmittedAt: timestampPtr(r.SubmittedAt, loc),
+			})
+		}
+		jr.Reviews[strconv.Itoa(number)] = list
+	}
+
+	for _, p := range rr.Projects {
+		jp := jsonProject{
+			Title:      p.Title,
+			Number:     p.Number,
+			Iterations: p.Iterations,
+			Items:      make([]jsonProjectItem, 0, len(p.Items)),
+		}
+		if jp.Iterations == nil {
+			jp.Iterations = []github.ProjectIteration{}
+		}
+		for _, item := range p.Items {
+			assignees := item.Assignees
+			if assignees == nil {
+				assignees = []string{}
+			}
+			jp.Items = append(jp.Items, jsonProjectItem{
+				Title:     item.Title,
+				Number:    item.Number,
+				URL:       item.URL,
+				State:     item.State,
+				Type:      item.Type,
+				Iteration: item.Iteration,
+				Status:    item.Status,
+				Assignees: assignees,
+				Fields:    jsonProjectFields(item.Fields),
+			})
+		}
+		jr.Projects = append(jr.Projects, jp)
+	}
+
+	return jr
+}
+
+// toJSONMember 将成员的工作和计划条目转换为 JSON 输出结构，切片字段始终非 nil。
+func toJSONMember(s MemberSummary) jsonMember {
+	jm := jsonMember{
+		User:      s.User,
+		WorkItems: make([]jsonWorkItem, 0, len(s.WorkItems)),
+		PlanItems: make([]jsonPlanItem, 0, len(s.PlanItems)),
+	}
+	for _, item := range s.WorkItems {
+		jm.WorkItems = append(jm.WorkItems, jsonWorkItem{
+			Type:         item.Type,
+			Repo:         item.Repo,
+			Number:       item.Number,
+			SHA:          item.SHA,
+			Title:        item.Title,
+			State:        item.State,
+			URL:          item.URL,
+			Date:         item.Date,
+			ReviewInfo:   item.ReviewInfo,
+			CI:           item.CI,
+			LinkedIssues: item.Issues,
+		})
+	}
+	for _, item := range s.PlanItems {
+		jm.PlanItems = append(jm.PlanItems, jsonPlanItem{
+			Source: item.Source,
+			Repo:   item.Repo,
+			Number: item.Number,
+			Title:  item.Title,
+			URL:    item.URL,
+			Status: item.Status,
+			Fields: item.Fields,
+			From:   item.From,
+			Age:    item.Age,
+			PR:     item.PR,
+		})
+	}
+	return jm
+}
+
+// jsonProjectFields 将工作项的自定义字段转换为 JSON 值，结果始终非 nil。
+func jsonProjectFields(fields map[string]github.ProjectFieldValue) map[string]any {
+	out := make(map[string]any, len(fields))
+	for name, v := range fields {
+		if v.Type == github.FieldNumber {
+			out[name] = v.Number
+		} else {
+			out[name] = v.Text
+		}
+	}
+	return out
+}
+
+// userLogins 提取 GitHub User 列表中的 login，结果始终非 nil。
+func userLogins(users []*gh.User) []string {
+	logins := make([]string, 0, len(users))
+	for _, u := range users {
+		logins = append(logins, u.GetLogin())
+	}
+	return logins
+}
+
+// timestampPtr 将可选的 GitHub 时间戳转换为 loc 时区的 *time.Time。
+func timestampPtr(ts *gh.Timestamp, loc *time.Location) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.In(loc)
+	return &t
+}
+
+// toJSONCI 返回指定 PR 的 CI 状态，没有检查时返回 nil。
+func toJSONCI(ci map[int]github.CIStatus, number int) *jsonCI {
+	status, ok := ci[number]
+	if !ok {
+		return nil
+	}
+	failing := status.Failing
+	if failing == nil {
+		failing = []string{}
+	}
+	return &jsonCI{State: status.State, Failing: failing}
+}
+
+// linkedIssueNumbers 提取关联 Issue 的编号，结果始终非 nil。
+func linkedIssueNumbers(linked []github.LinkedIssue) []int {
+	numbers := make([]int, 0, len(linked))
+	for _, li := range linked {
+		numbers = append(numbers, li.Number)
+	}
+	return numbers
+}
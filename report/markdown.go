@@ -0,0 +1,145 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// PrintMarkdown 以 Markdown 格式输出工作和计划条目，可直接粘贴到 Wiki 或聊天工具中。
+// 工作条目和计划条目均按仓库分组，不调用 AI。
+func PrintMarkdown(w io.Writer, reports []RepoReport, since, until time.Time, user string, rt ReportType) {
+	labels := labelsForType(rt)
+	workItems, planItems := extractItems(reports, user, since, until, rt)
+
+	fmt.Fprintf(w, "# %s（%s ~ %s）\n\n", labels.reportName, since.Format("2006-01-02"), until.Format("2006-01-02"))
+	if user != "" {
+		fmt.Fprintf(w, "> 用户: %s\n\n", displayUser(user))
+	}
+
+	fmt.Fprintf(w, "## %s\n\n", labels.workTitle)
+	writeMarkdownWork(w, workItems, rt)
+
+	fmt.Fprintf(w, "## %s\n\n", labels.planTitle)
+	writeMarkdownPlan(w, planItems)
+}
+
+// writeMarkdownWork 按仓库分组输出工作条目。
+// 非日报模式下，在条目前加上活动日期。
+func writeMarkdownWork(w io.Writer, items []WorkItem, rt ReportType) {
+	if len(items) == 0 {
+		fmt.Fprint(w, "_无工作数据_\n\n")
+		return
+	}
+
+	showDate := rt != ReportDaily
+	repos, groups := groupByRepo(items, func(item WorkItem) string { return item.Repo })
+	for _, repo := range repos {
+		fmt.Fprintf(w, "### %s\n\n", repo)
+		for _, item := range groups[repo] {
+			datePrefix := ""
+			if showDate && item.Date != "" {
+				datePrefix = item.Date + " "
+			}
+			var line string
+			switch item.Type {
+			case "pr":
+				line = fmt.Sprintf("- %s[PR #%d](%s) %s %s", datePrefix, item.Number, item.URL, escapeMarkdown(item.Title), markdownBadge(item.State))
+				if item.ReviewInfo != "" {
+					line += " · Review: " + item.ReviewInfo
+				}
+				if item.CI != "" {
+					line += " · CI: " + markdownBadge(item.CI)
+				}
+				if len(item.Issues) > 0 {
+					line += " · 关联 Issue: " + issueRefs(item.Issues)
+				}
+			case "issue":
+				line = fmt.Sprintf("- %s[Issue #%d](%s) %s %s", datePrefix, item.Number, item.URL, escapeMarkdown(item.Title), markdownBadge(item.State))
+			case "comment":
+				line = fmt.Sprintf("- %s[参与 Issue #%d 讨论](%s)", datePrefix, item.Number, item.URL)
+			case "review":
+				line = fmt.Sprintf("- %s[Review PR #%d](%s)", datePrefix, item.Number, item.URL)
+			case "commit":
+				line = fmt.Sprintf("- %s[`%s`](%s) %s", datePrefix, item.SHA, item.URL, escapeMarkdown(item.Title))
+			default:
+				continue
+			}
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeMarkdownPlan 按仓库分组输出计划条目，附带项目 Status 和 Review 请求的等待时长。
+func writeMarkdownPlan(w io.Writer, items []PlanItem) {
+	if len(items) == 0 {
+		fmt.Fprint(w, "_无计划数据_\n\n")
+		return
+	}
+
+	repos, groups := groupByRepo(items, func(item PlanItem) string { return item.Repo })
+	for _, repo := range repos {
+		fmt.Fprintf(w, "### %s\n\n", repo)
+		for _, item := range groups[repo] {
+			line := fmt.Sprintf("- [#%d](%s) %s", item.Number, item.URL, escapeMarkdown(item.Title))
+			if item.Status != "" {
+				line += " · Status: " + markdownBadge(item.Status)
+			}
+			if item.Fields != "" {
+				line += " · " + escapeMarkdown(item.Fields)
+			}
+			if item.Age != "" {
+				line += " · 等待 Review " + item.Age
+			}
+			if item.PR != 0 {
+				line += fmt.Sprintf(" · PR #%d", item.PR)
+			}
+			if item.From != "" {
+				line += " · 顺延自 " + escapeMarkdown(item.From)
+			}
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// groupByRepo 按仓库分组，保持仓库首次出现的顺序。
+func groupByRepo[T any](items []T, repoOf func(T) string) ([]string, map[string][]T) {
+	var repos []string
+	groups := make(map[string][]T)
+	for _, item := range items {
+		repo := repoOf(item)
+		if _, ok := groups[repo]; !ok {
+			repos = append(repos, repo)
+		}
+		groups[repo] = append(groups[repo], item)
+	}
+	return repos, groups
+}
+
+// markdownBadge 将状态渲染为行内代码样式的徽标，如 `merged`。
+func markdownBadge(state string) string {
+	if state == "" {
+		return ""
+	}
+	return "`" + state + "`"
+}
+
+// markdownEscaper 转义标题中会破坏 Markdown 链接和强调语法的字符。
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+// escapeMarkdown 转义 Markdown 特殊字符。
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -15,25 +15,28 @@ import (
 )
 
 // Print 将完整的活动报告以 CSV 分段格式写入 writer。
-// 按 5 个类别分段输出：Issues、Pull Requests、Issue Comments、Review Comments、Project Items。
+// 按 7 个类别分段输出：Issues、Pull Requests、Issue Comments、Review Comments、Commits、Review Requests、Project Items。
 // 每段格式为：段标题行 → CSV 表头行 → 数据行，段之间空行分隔。跳过没有数据的段。
+// 迭代分类以 until 为参考时间，日期按 until 所在时区格式化。
 func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 	cw := csv.NewWriter(w)
 	defer cw.Flush()
 
-	// 收集所有仓库的 5 类数据
+	loc := until.Location()
+
+	// 收集所有仓库的 7 类数据
 	var (
 		issueRows         [][]string
 		prRows            [][]string
 		issueCommentRows  [][]string
 		reviewCommentRows [][]string
+		commitRows        [][]string
+		reviewRequestRows [][]string
 		projectItemRows   [][]string
 	)
 
-	now := time.Now()
-
 	for _, rr := range reports {
-		fullRepo := rr.Owner + "/" + rr.Repo
+		fullRepo := rr.FullName()
 
 		// Issues
 		for _, issue := range rr.Issues {
@@ -43,7 +46,7 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 				issue.GetTitle(),
 				issue.GetState(),
 				issue.GetUser().GetLogin(),
-				issue.GetUpdatedAt().Format("2006-01-02"),
+				formatDate(issue.GetUpdatedAt().Time, loc),
 			})
 		}
 
@@ -56,8 +59,9 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 				pr.GetTitle(),
 				prDisplayState(pr),
 				pr.GetUser().GetLogin(),
-				pr.GetUpdatedAt().Format("2006-01-02"),
+				formatDate(pr.GetUpdatedAt().Time, loc),
 				reviews,
+				ciSummary(rr.CI[pr.GetNumber()]),
 			})
 		}
 
@@ -69,7 +73,7 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 				fullRepo,
 				num,
 				c.GetUser().GetLogin(),
-				c.GetCreatedAt().Format("2006-01-02"),
+				formatDate(c.GetCreatedAt().Time, loc),
 				body,
 			})
 		}
@@ -82,18 +86,43 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 				fullRepo,
 				num,
 				c.GetUser().GetLogin(),
-				c.GetCreatedAt().Format("2006-01-02"),
+				formatDate(c.GetCreatedAt().Time, loc),
 				c.GetPath(),
 				body,
 			})
 		}
 
+		// Commits
+		for _, c := range rr.Commits {
+			commitRows = append(commitRows, []string{
+				fullRepo,
+				shortSHA(c.GetSHA()),
+				c.GetAuthor().GetLogin(),
+				formatDate(commitDate(c), loc),
+				truncate(commitTitle(c), 80),
+			})
+		}
+
+		// Review Requests
+		for _, req := range rr.ReviewRequests {
+			reviewRequestRows = append(reviewRequestRows, []string{
+				fullRepo,
+				strconv.Itoa(req.Number),
+				req.Title,
+				req.Author,
+				req.Reviewer,
+				req.Team,
+				formatDate(req.RequestedAt, loc),
+				requestAge(req.RequestedAt, until),
+			})
+		}
+
 		// Project Items
 		for _, project := range rr.Projects {
 			// 检查该项目是否有与当前仓库相关的工作项
 			hasRepoItems := false
 			for _, item := range project.Items {
-				if strings.Contains(item.URL, fullRepo) {
+				if rr.ownsURL(item.URL) {
 					hasRepoItems = true
 					break
 				}
@@ -102,7 +131,7 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 				continue
 			}
 
-			relevant := github.FindRelevantIterations(project.Iterations, now)
+			relevant := github.FindRelevantIterations(project.Iterations, until)
 
 			type iterEntry struct {
 				category  string
@@ -119,7 +148,7 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 					continue
 				}
 				for _, item := range project.Items {
-					if item.Iteration != entry.iteration.Title || !strings.Contains(item.URL, fullRepo) {
+					if item.Iteration != entry.iteration.Title || !rr.ownsURL(item.URL) {
 						continue
 					}
 					projectItemRows = append(projectItemRows, []string{
@@ -148,7 +177,7 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 
 	if len(prRows) > 0 {
 		writeSection(cw, w, &first, "Pull Requests",
-			[]string{"Repo", "Number", "Title", "State", "User", "Date", "Reviews"},
+			[]string{"Repo", "Number", "Title", "State", "User", "Date", "Reviews", "CI"},
 			prRows)
 	}
 
@@ -164,6 +193,18 @@ func Print(w io.Writer, reports []RepoReport, since, until time.Time) {
 			reviewCommentRows)
 	}
 
+	if len(commitRows) > 0 {
+		writeSection(cw, w, &first, "Commits",
+			[]string{"Repo", "SHA", "Author", "Date", "Message"},
+			commitRows)
+	}
+
+	if len(reviewRequestRows) > 0 {
+		writeSection(cw, w, &first, "Review Requests",
+			[]string{"Repo", "Number", "Title", "Author", "Reviewer", "Team", "Requested", "Age"},
+			reviewRequestRows)
+	}
+
 	if len(projectItemRows) > 0 {
 		writeSection(cw, w, &first, "Project Items",
 			[]string{"Repo", "Project", "Iteration", "Category", "Number", "Title", "State", "Status"},
@@ -214,6 +255,14 @@ func buildReviewSummary(reviews []*gh.PullRequestReview) string {
 	return strings.Join(parts, "; ")
 }
 
+// ciSummary 构建 CI 状态的摘要字符串，格式如 "failure: lint, test"，没有检查时为空。
+func ciSummary(ci github.CIStatus) string {
+	if len(ci.Failing) == 0 {
+		return ci.State
+	}
+	return ci.State + ": " + strings.Join(ci.Failing, ", ")
+}
+
 // prDisplayState 返回 Pull Request 的可读状态。
 func prDisplayState(pr *gh.PullRequest) string {
 	if pr.MergedAt != nil {
@@ -225,6 +274,21 @@ func prDisplayState(pr *gh.PullRequest) string {
 	return pr.GetState()
 }
 
+// prStateAt 返回 Pull Request 在指定时间点的可读状态。
+// 在 t 之后才合并或关闭的 PR 视为 t 时仍处于 open（或 draft）状态，用于回溯生成历史报告。
+func prStateAt(pr *gh.PullRequest, t time.Time) string {
+	if pr.MergedAt != nil && !pr.MergedAt.After(t) {
+		return "merged"
+	}
+	if pr.ClosedAt != nil && !pr.ClosedAt.After(t) {
+		return "closed"
+	}
+	if pr.GetDraft() {
+		return "draft"
+	}
+	return "open"
+}
+
 // issueNumberRe 用于从 URL 中提取 Issue/PR 编号。
 var issueNumberRe = regexp.MustCompile(`/(\d+)$`)
 
@@ -243,6 +307,12 @@ func extractNumber(url string) string {
 	return "?"
 }
 
+// formatDate 将时间转换到 loc 时区后格式化为日期（2006-01-02）。
+// GitHub 返回的时间戳均为 UTC，按天归类前必须先转换到报告时区。
+func formatDate(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02")
+}
+
 // truncate 将字符串截断到指定的最大长度，超出部分用 "..." 替代。
 func truncate(s string, maxLen int) string {
 	s = strings.ReplaceAll(s, "\n", " ")
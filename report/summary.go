@@ -3,6 +3,7 @@ package report
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,8 @@ const (
 	ReportMonthly ReportType = "monthly"
 	// ReportYearly 年报。
 	ReportYearly ReportType = "yearly"
+	// ReportIteration 迭代报告，时间范围为 Projects v2 的一个迭代。
+	ReportIteration ReportType = "iteration"
 )
 
 // reportTypeLabels 定义各报告类型的显示文本。
@@ -32,6 +35,7 @@ type reportTypeLabels struct {
 	planTitle    string // 计划标题（如 "明日计划"）
 	roleName     string // AI 角色名称（如 "工作日报助手"）
 	reportName   string // 报告名称（如 "日报"）
+	workDesc     string // 工作来源说明（仅迭代报告）
 	planDesc     string // 计划来源说明
 	noPlanStatus string // 计划状态排除说明
 	dateHint     string // 日期格式提示（非日报时提示 AI 保留日期前缀）
@@ -46,7 +50,7 @@ func labelsForType(rt ReportType) reportTypeLabels {
 			planTitle:    "下周计划",
 			roleName:     "工作周报助手",
 			reportName:   "周报",
-			planDesc:     "下周计划来自未完成的 PR 和当前迭代中未完成的工作项",
+			planDesc:     "下周计划来自未完成的 PR（或其关联的 Issue）、当前迭代中未完成的工作项和等待 Review 的 PR",
 			noPlanStatus: "下周计划不要包含任何状态或优先级标识（如 Testing、开发中、Todo、P0、P1 等）",
 			dateHint:     "每条工作记录前有日期前缀，请在输出中保留该日期",
 		}
@@ -56,17 +60,28 @@ func labelsForType(rt ReportType) reportTypeLabels {
 			planTitle:    "下月计划",
 			roleName:     "工作月报助手",
 			reportName:   "月报",
-			planDesc:     "下月计划来自未完成的 PR 和当前迭代中未完成的工作项",
+			planDesc:     "下月计划来自未完成的 PR（或其关联的 Issue）、当前迭代中未完成的工作项和等待 Review 的 PR",
 			noPlanStatus: "下月计划不要包含任何状态或优先级标识（如 Testing、开发中、Todo、P0、P1 等）",
 			dateHint:     "每条工作记录前有日期前缀，请在输出中保留该日期",
 		}
+	case ReportIteration:
+		return reportTypeLabels{
+			workTitle:    "本迭代完成",
+			planTitle:    "下迭代计划",
+			roleName:     "迭代报告助手",
+			reportName:   "迭代报告",
+			workDesc:     "本迭代完成的工作来自迭代中在迭代期间完成（Status 为 Done 或已关闭、已合并）的工作项，状态 done（项目中已完成但 Issue / PR 未关闭）写\"已完成\"",
+			planDesc:     "下迭代计划来自下一迭代中的工作项（next_iteration），以及上一迭代和本迭代中仍未完成的顺延工作项（carry_over，描述中注明\"顺延\"）",
+			noPlanStatus: "下迭代计划不要包含任何状态或优先级标识（如 Testing、开发中、Todo、P0、P1 等）",
+			dateHint:     "每条工作记录前有日期前缀，请在输出中保留该日期",
+		}
 	case ReportYearly:
 		return reportTypeLabels{
 			workTitle:    "年度工作",
 			planTitle:    "下年计划",
 			roleName:     "年度总结助手",
 			reportName:   "年报",
-			planDesc:     "下年计划来自未完成的 PR 和当前迭代中未完成的工作项",
+			planDesc:     "下年计划来自未完成的 PR（或其关联的 Issue）、当前迭代中未完成的工作项和等待 Review 的 PR",
 			noPlanStatus: "下年计划不要包含任何状态或优先级标识（如 Testing、开发中、Todo、P0、P1 等）",
 			dateHint:     "每条工作记录前有日期前缀，请在输出中保留该日期",
 		}
@@ -76,16 +91,15 @@ func labelsForType(rt ReportType) reportTypeLabels {
 			planTitle:    "明日计划",
 			roleName:     "工作日报助手",
 			reportName:   "日报",
-			planDesc:     "明日计划来自未完成的 PR 和当前迭代中未完成的工作项",
+			planDesc:     "明日计划来自未完成的 PR（或其关联的 Issue）、当前迭代中未完成的工作项和等待 Review 的 PR",
 			noPlanStatus: "明日计划不要包含任何状态或优先级标识（如 Testing、开发中、Todo、P0、P1 等）",
 		}
 	}
 }
 
-// workTimeCutoff 根据报告类型计算工作条目的时间过滤基准。
+// WorkTimeCutoff 根据报告类型计算 now 所在周期的起点，作为工作条目的默认时间过滤基准。
 // 日报：当天零点；周报：本周一零点；月报：本月一号零点；年报：今年一月一号零点。
-func workTimeCutoff(rt ReportType) time.Time {
-	now := time.Now()
+func WorkTimeCutoff(rt ReportType, now time.Time) time.Time {
 	switch rt {
 	case ReportWeekly:
 		// 本周一零点
@@ -106,14 +120,17 @@ func workTimeCutoff(rt ReportType) time.Time {
 
 // WorkItem 表示一条工作活动。
 type WorkItem struct {
-	Type       string // "pr", "issue", "comment", "review"
+	Type       string // "pr", "issue", "comment", "review", "commit"
 	Repo       string // "owner/repo"
 	Number     int
 	Title      string
 	State      string // "merged", "open", "closed", "draft"
 	URL        string
 	ReviewInfo string // PR 的 review 摘要
+	CI         string // PR head 提交的 CI 摘要（如 "failure: lint"），没有检查时为空
+	Issues     []int  // PR 关联的 Issue 编号，这些 Issue 不再单独列为工作条目
 	Date       string // 活动日期，格式 "2006-01-02"
+	SHA        string // 提交的短 SHA（仅 commit 类型）
 }
 
 // PlanItem 表示明日计划的一条项目。
@@ -122,22 +139,27 @@ type PlanItem struct {
 	Number int
 	Title  string
 	URL    string
-	Status string // Project item status（如 "P0", "In Development"）
-	Source string // "open_pr" 或 "project_item"
+	Status string // Project item status（如 "In Development"）
+	Fields string // Project item 的其他自定义字段（如 "Priority: P0, Estimate: 3"）
+	Source string // "open_pr"、"linked_issue"、"project_item"、"review_request"，迭代报告为 "next_iteration" 或 "carry_over"
+	From   string // 顺延工作项原来所在的迭代（仅 carry_over）
+	Age    string // Review 请求已等待的时长（仅 review_request，如 "3 天"）
+	PR     int    // 正在解决该 Issue 的 open PR 编号（仅 linked_issue）
 }
 
-// extractWorkItems 从报告数据中提取工作条目。
-// 根据 reportType 确定时间过滤基准：日报用当天零点，周报用本周一零点，月报用本月一号零点，年报用今年一月一号零点。
-func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkItem {
-	cutoff := workTimeCutoff(rt)
+// extractWorkItems 从报告数据中提取 [since, until] 时间范围内的工作条目。
+func extractWorkItems(reports []RepoReport, user string, since, until time.Time) []WorkItem {
+	user = filterLogin(user)
 	var items []WorkItem
 	// 记录用户作为 PR 作者的所有条目（不限日期），用于去重评论和 review
 	prAuthorKeys := make(map[string]bool)
 	// 记录已纳入工作的 Issue，用于去重评论
 	issueKeys := make(map[string]bool)
+	// 记录已合并到 PR 条目中的关联 Issue，这些 Issue 不再单独列出
+	linkedKeys := make(map[string]bool)
 
 	for _, rr := range reports {
-		fullRepo := rr.Owner + "/" + rr.Repo
+		fullRepo := rr.FullName()
 
 		// 用户的 PR：收集 prAuthorKeys 用于评论/review 去重，同时过滤今日活动条目
 		for _, pr := range rr.PullRequests {
@@ -146,12 +168,20 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 			}
 			prAuthorKeys[fmt.Sprintf("%s#%d", fullRepo, pr.GetNumber())] = true
 
-			if !prHasActivitySince(pr, cutoff) {
+			if !prHasActivitySince(pr, since, until) {
 				continue
 			}
-			state := prDisplayState(pr)
+			state := prStateAt(pr, until)
 			reviews := buildReviewSummary(rr.Reviews[pr.GetNumber()])
 
+			var linked []int
+			for _, li := range rr.LinkedIssues[pr.GetNumber()] {
+				key := fmt.Sprintf("%s#%d", fullRepo, li.Number)
+				linkedKeys[key] = true
+				issueKeys[key] = true
+				linked = append(linked, li.Number)
+			}
+
 			items = append(items, WorkItem{
 				Type:       "pr",
 				Repo:       fullRepo,
@@ -160,7 +190,9 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 				State:      state,
 				URL:        pr.GetHTMLURL(),
 				ReviewInfo: reviews,
-				Date:       prActivityDate(pr),
+				CI:         ciSummary(rr.CI[pr.GetNumber()]),
+				Issues:     linked,
+				Date:       prActivityDate(pr, until),
 			})
 		}
 
@@ -173,7 +205,11 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 			if user != "" && len(issue.Assignees) > 0 && !hasAssignee(issue.Assignees, user) {
 				continue
 			}
-			if !issueWorkedSince(issue, cutoff) {
+			if !issueWorkedSince(issue, since, until) {
+				continue
+			}
+			// 已作为关联 Issue 合并到 PR 条目中
+			if linkedKeys[fmt.Sprintf("%s#%d", fullRepo, issue.GetNumber())] {
 				continue
 			}
 			issueKeys[fmt.Sprintf("%s#%d", fullRepo, issue.GetNumber())] = true
@@ -182,9 +218,9 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 				Repo:   fullRepo,
 				Number: issue.GetNumber(),
 				Title:  issue.GetTitle(),
-				State:  issue.GetState(),
+				State:  issueStateAt(issue, until),
 				URL:    issue.GetHTMLURL(),
-				Date:   issueActivityDate(issue),
+				Date:   issueActivityDate(issue, until),
 			})
 		}
 
@@ -194,7 +230,7 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 			if user != "" && c.GetUser().GetLogin() != user {
 				continue
 			}
-			if c.GetCreatedAt().Before(cutoff) {
+			if c.GetCreatedAt().Before(since) || c.GetCreatedAt().After(until) {
 				continue
 			}
 			num := extractNumber(c.GetIssueURL())
@@ -218,7 +254,7 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 				Number: n,
 				Title:  fmt.Sprintf("Commented on #%s", num),
 				URL:    c.GetHTMLURL(),
-				Date:   c.GetCreatedAt().Format("2006-01-02"),
+				Date:   formatDate(c.GetCreatedAt().Time, until.Location()),
 			})
 		}
 
@@ -228,7 +264,7 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 			if user != "" && c.GetUser().GetLogin() != user {
 				continue
 			}
-			if c.GetCreatedAt().Before(cutoff) {
+			if c.GetCreatedAt().Before(since) || c.GetCreatedAt().After(until) {
 				continue
 			}
 			num := extractNumber(c.GetPullRequestURL())
@@ -247,7 +283,26 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 				Number: n,
 				Title:  fmt.Sprintf("Reviewed PR #%s", num),
 				URL:    c.GetHTMLURL(),
-				Date:   c.GetCreatedAt().Format("2006-01-02"),
+				Date:   formatDate(c.GetCreatedAt().Time, until.Location()),
+			})
+		}
+
+		// 提交（collector 已排除被 PR 覆盖的提交；只保留时间范围内的提交）
+		for _, c := range rr.Commits {
+			if user != "" && c.GetAuthor().GetLogin() != user {
+				continue
+			}
+			date := commitDate(c)
+			if date.Before(since) || date.After(until) {
+				continue
+			}
+			items = append(items, WorkItem{
+				Type:  "commit",
+				Repo:  fullRepo,
+				Title: commitTitle(c),
+				URL:   c.GetHTMLURL(),
+				Date:  formatDate(date, until.Location()),
+				SHA:   shortSHA(c.GetSHA()),
 			})
 		}
 	}
@@ -255,16 +310,34 @@ func extractWorkItems(reports []RepoReport, user string, rt ReportType) []WorkIt
 	return items
 }
 
+// commitTitle 返回提交信息的第一行。
+func commitTitle(c *gh.RepositoryCommit) string {
+	title, _, _ := strings.Cut(c.GetCommit().GetMessage(), "\n")
+	return strings.TrimSpace(title)
+}
+
+// shortSHA 返回 7 位短 SHA。
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 // extractPlanItems 从报告数据中提取明日计划条目。
-func extractPlanItems(reports []RepoReport, user string) []PlanItem {
+// 以 until 为参考时间判断 PR 状态和当前迭代。
+func extractPlanItems(reports []RepoReport, user string, until time.Time) []PlanItem {
+	user = filterLogin(user)
 	var items []PlanItem
 	seen := make(map[string]int) // 按 owner/repo#number 去重，值为 items 中的索引
 
-	now := time.Now()
-
-	// 来源 1：未合并且未关闭的 PR
+	// 来源 1：未合并且未关闭的 PR；PR 关联了未关闭的 Issue 时，改为列出这些 Issue（linked_issue）
 	for _, rr := range reports {
-		fullRepo := rr.Owner + "/" + rr.Repo
+		fullRepo := rr.FullName()
+		issueStates := make(map[int]string, len(rr.Issues))
+		for _, issue := range rr.Issues {
+			issueStates[issue.GetNumber()] = issueStateAt(issue, until)
+		}
 		for _, pr := range rr.PullRequests {
 			if user != "" && pr.GetUser().GetLogin() != user {
 				continue
@@ -273,7 +346,7 @@ func extractPlanItems(reports []RepoReport, user string) []PlanItem {
 			if user != "" && len(pr.Assignees) > 0 && !hasAssignee(pr.Assignees, user) {
 				continue
 			}
-			state := prDisplayState(pr)
+			state := prStateAt(pr, until)
 			if state == "merged" || state == "closed" {
 				continue
 			}
@@ -281,6 +354,30 @@ func extractPlanItems(reports []RepoReport, user string) []PlanItem {
 			if _, ok := seen[key]; ok {
 				continue
 			}
+			first := len(items)
+			for _, li := range rr.LinkedIssues[pr.GetNumber()] {
+				state := li.State
+				if s, ok := issueStates[li.Number]; ok {
+					state = s
+				}
+				issueKey := fmt.Sprintf("%s#%d", fullRepo, li.Number)
+				if _, ok := seen[issueKey]; ok || state != "open" {
+					continue
+				}
+				seen[issueKey] = len(items)
+				items = append(items, PlanItem{
+					Repo:   fullRepo,
+					Number: li.Number,
+					Title:  li.Title,
+					URL:    li.URL,
+					Source: "linked_issue",
+					PR:     pr.GetNumber(),
+				})
+			}
+			if len(items) > first {
+				seen[key] = first
+				continue
+			}
 			seen[key] = len(items)
 			items = append(items, PlanItem{
 				Repo:   fullRepo,
@@ -294,9 +391,9 @@ func extractPlanItems(reports []RepoReport, user string) []PlanItem {
 
 	// 来源 2：当前迭代中未完成的项目
 	for _, rr := range reports {
-		fullRepo := rr.Owner + "/" + rr.Repo
+		fullRepo := rr.FullName()
 		for _, project := range rr.Projects {
-			relevant := github.FindRelevantIterations(project.Iterations, now)
+			relevant := github.FindRelevantIterations(project.Iterations, until)
 			if relevant.Current == nil {
 				continue
 			}
@@ -304,7 +401,7 @@ func extractPlanItems(reports []RepoReport, user string) []PlanItem {
 				if item.Iteration != relevant.Current.Title {
 					continue
 				}
-				if !strings.Contains(item.URL, fullRepo) {
+				if !rr.ownsURL(item.URL) {
 					continue
 				}
 				// 指定用户时，只纳入 Assignees 包含该用户的项目
@@ -312,9 +409,7 @@ func extractPlanItems(reports []RepoReport, user string) []PlanItem {
 					continue
 				}
 				// 跳过已完成的项目
-				upperStatus := strings.ToUpper(item.Status)
-				upperState := strings.ToUpper(item.State)
-				if upperStatus == "DONE" || upperState == "CLOSED" || upperState == "MERGED" {
+				if projectItemDone(item) {
 					continue
 				}
 				key := fmt.Sprintf("%s#%d", fullRepo, item.Number)
@@ -323,6 +418,9 @@ func extractPlanItems(reports []RepoReport, user string) []PlanItem {
 					if items[idx].Status == "" {
 						items[idx].Status = item.Status
 					}
+					if items[idx].Fields == "" {
+						items[idx].Fields = projectFieldsText(item.Fields)
+					}
 					continue
 				}
 				seen[key] = len(items)
@@ -332,34 +430,88 @@ func extractPlanItems(reports []RepoReport, user string) []PlanItem {
 					Title:  item.Title,
 					URL:    item.URL,
 					Status: item.Status,
+					Fields: projectFieldsText(item.Fields),
 					Source: "project_item",
 				})
 			}
 		}
 	}
 
+	// 来源 3：等待用户 Review 的他人 PR，等待最久的排在前面
+	type pendingReview struct {
+		repo string
+		req  ReviewRequest
+	}
+	var pending []pendingReview
+	for _, rr := range reports {
+		for _, req := range rr.ReviewRequests {
+			if user != "" && req.Reviewer != user {
+				continue
+			}
+			pending = append(pending, pendingReview{rr.FullName(), req})
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].req.RequestedAt.Before(pending[j].req.RequestedAt)
+	})
+	for _, p := range pending {
+		key := fmt.Sprintf("%s#%d", p.repo, p.req.Number)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = len(items)
+		items = append(items, PlanItem{
+			Repo:   p.repo,
+			Number: p.req.Number,
+			Title:  p.req.Title,
+			URL:    p.req.URL,
+			Source: "review_request",
+			Age:    requestAge(p.req.RequestedAt, until),
+		})
+	}
+
 	return items
 }
 
-// prActivityDate 返回 PR 最具代表性的活动日期。
+// requestAge 返回 Review 请求截至 until 已等待的时长，不足一天时按小时计。
+func requestAge(requestedAt, until time.Time) string {
+	d := until.Sub(requestedAt)
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%d 小时", max(int(d.Hours()), 1))
+	}
+	return fmt.Sprintf("%d 天", int(d.Hours()/24))
+}
+
+// prActivityDate 返回 PR 截至 until 最具代表性的活动日期（按 until 所在时区）。
 // 优先级：merged > closed > created。
-func prActivityDate(pr *gh.PullRequest) string {
-	if pr.MergedAt != nil {
-		return pr.MergedAt.Format("2006-01-02")
+func prActivityDate(pr *gh.PullRequest, until time.Time) string {
+	loc := until.Location()
+	if pr.MergedAt != nil && !pr.MergedAt.After(until) {
+		return formatDate(pr.MergedAt.Time, loc)
 	}
-	if pr.ClosedAt != nil {
-		return pr.ClosedAt.Format("2006-01-02")
+	if pr.ClosedAt != nil && !pr.ClosedAt.After(until) {
+		return formatDate(pr.ClosedAt.Time, loc)
 	}
-	return pr.GetCreatedAt().Format("2006-01-02")
+	return formatDate(pr.GetCreatedAt().Time, loc)
 }
 
-// issueActivityDate 返回 Issue 最具代表性的活动日期。
+// issueActivityDate 返回 Issue 截至 until 最具代表性的活动日期（按 until 所在时区）。
 // 优先级：closed > created。
-func issueActivityDate(issue *gh.Issue) string {
-	if issue.ClosedAt != nil {
-		return issue.ClosedAt.Format("2006-01-02")
+func issueActivityDate(issue *gh.Issue, until time.Time) string {
+	loc := until.Location()
+	if issue.ClosedAt != nil && !issue.ClosedAt.After(until) {
+		return formatDate(issue.ClosedAt.Time, loc)
 	}
-	return issue.GetCreatedAt().Format("2006-01-02")
+	return formatDate(issue.GetCreatedAt().Time, loc)
+}
+
+// issueStateAt 返回 Issue 在指定时间点的状态。
+// 在 t 之后才关闭的 Issue 视为 t 时仍处于 open 状态。
+func issueStateAt(issue *gh.Issue, t time.Time) string {
+	if issue.GetState() == "closed" && issue.ClosedAt != nil && issue.ClosedAt.After(t) {
+		return "open"
+	}
+	return issue.GetState()
 }
 
 // buildPromptTemplate 构建 Prompt 指令模板，根据报告类型调整措辞。
@@ -378,9 +530,11 @@ func buildPromptTemplate(since, until time.Time, user string, rt ReportType) str
 	sb.WriteString("<计划描述>, <URL>\n\n")
 	sb.WriteString("格式要求:\n")
 	sb.WriteString("- 每条记录一行\n")
-	sb.WriteString("- PR 状态映射: merged→已合并, open(有 review)→已提交(审查中), open(无 review)→已提交, draft→草稿, closed→已关闭\n")
-	sb.WriteString("- Issue 状态: open→进行中, closed→已关闭\n")
-	sb.WriteString("- 评论和 review 类型的活动描述参考格式: 参与 Issue #N / Review PR #N 讨论\n")
+	sb.WriteString(workStatePromptRules)
+	fmt.Fprintf(&sb, reviewRequestPromptRule, "用户")
+	if labels.workDesc != "" {
+		fmt.Fprintf(&sb, "- %s\n", labels.workDesc)
+	}
 	fmt.Fprintf(&sb, "- %s\n", labels.planDesc)
 	fmt.Fprintf(&sb, "- %s\n", labels.noPlanStatus)
 	if labels.dateHint != "" {
@@ -391,6 +545,28 @@ func buildPromptTemplate(since, until time.Time, user string, rt ReportType) str
 	return sb.String()
 }
 
+// workStatePromptRules 是工作条目的状态映射和描述规则，完整报告、团队报告和分段总结的 Prompt 共用。
+const workStatePromptRules = "- PR 状态映射: merged→已合并, open(有 review)→已提交(审查中), open(无 review)→已提交, draft→草稿, closed→已关闭\n" +
+	ciPromptRule +
+	linkedIssuePromptRule +
+	"- Issue 状态: open→进行中, closed→已关闭\n" +
+	"- 评论和 review 类型的活动描述参考格式: 参与 Issue #N / Review PR #N 讨论\n" +
+	commitPromptRule
+
+// commitPromptRule 是未经 PR 直接推送的提交的描述规则。
+const commitPromptRule = "- Commit 为未经 PR 直接推送的提交，状态写\"已提交\"，同一主题的多个提交可合并为一条\n"
+
+// reviewRequestPromptRule 是 review_request 计划条目的描述规则，%s 为等待 Review 的人（如 "用户"、"该成员"）。
+const reviewRequestPromptRule = "- review_request 为等待%s Review 的他人 PR，计划描述参考格式: Review PR #N（已等待 N 天），等待较久的排在前面\n"
+
+// ciPromptRule 是 PR CI 状态的映射规则，仅对 open/draft 的 PR 生效。
+const ciPromptRule = "- open/draft PR 的 CI 映射: success→CI 通过, failure→CI 失败（冒号后为失败的检查，可择要列出）, pending→CI 运行中；" +
+	"与状态合并描述，如\"已提交，CI 失败\"、\"已提交，CI 通过，等待 Review\"；没有 CI 字段时不提及 CI\n"
+
+// linkedIssuePromptRule 是 PR 关联 Issue 的合并规则。
+const linkedIssuePromptRule = "- PR 的关联 Issue 已合并到 PR 条目中，描述为一条工作（如\"修复 #12 登录失败问题（PR #34）\"），不要单独列出这些 Issue；" +
+	"linked_issue 计划条目为 PR 仍在进行中的 Issue\n"
+
 // formatWorkData 将工作数据格式化为文本。
 // 非日报模式下，在标题前加上活动日期。
 func formatWorkData(items []WorkItem, rt ReportType) string {
@@ -406,8 +582,15 @@ func formatWorkData(items []WorkItem, rt ReportType) string {
 		}
 		switch item.Type {
 		case "pr":
-			fmt.Fprintf(&sb, "- [PR] %s#%d %s%s | 状态: %s | Review: %s | %s\n",
-				item.Repo, item.Number, datePrefix, item.Title, item.State, item.ReviewInfo, item.URL)
+			extra := ""
+			if item.CI != "" {
+				extra += " | CI: " + item.CI
+			}
+			if len(item.Issues) > 0 {
+				extra += " | 关联 Issue: " + issueRefs(item.Issues)
+			}
+			fmt.Fprintf(&sb, "- [PR] %s#%d %s%s | 状态: %s | Review: %s%s | %s\n",
+				item.Repo, item.Number, datePrefix, item.Title, item.State, item.ReviewInfo, extra, item.URL)
 		case "issue":
 			fmt.Fprintf(&sb, "- [Issue] %s#%d %s%s | 状态: %s | %s\n",
 				item.Repo, item.Number, datePrefix, item.Title, item.State, item.URL)
@@ -417,11 +600,39 @@ func formatWorkData(items []WorkItem, rt ReportType) string {
 		case "review":
 			fmt.Fprintf(&sb, "- [Review] %s#%d %s%s | %s\n",
 				item.Repo, item.Number, datePrefix, item.Title, item.URL)
+		case "commit":
+			fmt.Fprintf(&sb, "- [Commit] %s@%s %s%s | %s\n",
+				item.Repo, item.SHA, datePrefix, item.Title, item.URL)
 		}
 	}
 	return sb.String()
 }
 
+// issueRefs 将 Issue 编号格式化为 "#12, #13"。
+func issueRefs(numbers []int) string {
+	refs := make([]string, len(numbers))
+	for i, n := range numbers {
+		refs[i] = "#" + strconv.Itoa(n)
+	}
+	return strings.Join(refs, ", ")
+}
+
+// projectFieldsText 将工作项的自定义字段按字段名排序格式化为 "Priority: P0, Estimate: 3"，跳过空值。
+func projectFieldsText(fields map[string]github.ProjectFieldValue) string {
+	names := make([]string, 0, len(fields))
+	for name, v := range fields {
+		if v.String() != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ": " + fields[name].String()
+	}
+	return strings.Join(parts, ", ")
+}
+
 // formatPlanData 将计划数据格式化为文本。
 func formatPlanData(items []PlanItem) string {
 	if len(items) == 0 {
@@ -433,6 +644,18 @@ func formatPlanData(items []PlanItem) string {
 		if item.Status != "" {
 			status = fmt.Sprintf(" | Status: %s", item.Status)
 		}
+		if item.Fields != "" {
+			status += " | " + item.Fields
+		}
+		if item.Age != "" {
+			status += fmt.Sprintf(" | 已等待: %s", item.Age)
+		}
+		if item.PR != 0 {
+			status += fmt.Sprintf(" | PR: #%d", item.PR)
+		}
+		if item.From != "" {
+			status += fmt.Sprintf(" | 原迭代: %s", item.From)
+		}
 		sb.WriteString(fmt.Sprintf("- [%s] %s#%d %s%s | %s\n",
 			item.Source, item.Repo, item.Number, item.Title, status, item.URL))
 	}
@@ -442,8 +665,7 @@ func formatPlanData(items []PlanItem) string {
 // PrintSummaryData 输出结构化的工作和计划数据，以及可供手动粘贴给 AI 的 Prompt 模板。
 func PrintSummaryData(w io.Writer, reports []RepoReport, since, until time.Time, user string, rt ReportType) {
 	labels := labelsForType(rt)
-	workItems := extractWorkItems(reports, user, rt)
-	planItems := extractPlanItems(reports, user)
+	workItems, planItems := extractItems(reports, user, since, until, rt)
 
 	// 输出结构化数据
 	fmt.Fprintf(w, "========== %s ==========\n", labels.workTitle)
@@ -461,8 +683,7 @@ func PrintSummaryData(w io.Writer, reports []RepoReport, since, until time.Time,
 
 // BuildSummaryPrompt 构建完整的 Prompt 文本，供 API 调用或手动粘贴。
 func BuildSummaryPrompt(reports []RepoReport, since, until time.Time, user string, rt ReportType) string {
-	workItems := extractWorkItems(reports, user, rt)
-	planItems := extractPlanItems(reports, user)
+	workItems, planItems := extractItems(reports, user, since, until, rt)
 	return buildSummaryPromptFromItems(workItems, planItems, since, until, user, rt)
 }
 
@@ -481,6 +702,28 @@ func buildSummaryPromptFromItems(workItems []WorkItem, planItems []PlanItem, sin
 	return sb.String()
 }
 
+// IsTeamRef 判断 user 是否为团队引用（如 "@org/team"）。
+func IsTeamRef(user string) bool {
+	return strings.HasPrefix(user, "@") && strings.Contains(user, "/")
+}
+
+// filterLogin 返回提取条目时用于过滤的 login。
+// 团队引用的成员过滤已在 collector 层完成（Options.Users），提取条目时不再按单个 login 过滤。
+func filterLogin(user string) string {
+	if IsTeamRef(user) {
+		return ""
+	}
+	return user
+}
+
+// displayUser 返回用于展示的用户名，统一带 @ 前缀。
+func displayUser(user string) string {
+	if user == "" || strings.HasPrefix(user, "@") {
+		return user
+	}
+	return "@" + user
+}
+
 // hasAssignee 检查 GitHub User 列表中是否包含指定用户。
 func hasAssignee(assignees []*gh.User, login string) bool {
 	for _, a := range assignees {
@@ -491,10 +734,14 @@ func hasAssignee(assignees []*gh.User, login string) bool {
 	return false
 }
 
-// issueWorkedSince 判断 Issue 是否应纳入今日工作。
-// open 状态的 Issue 视为进行中的工作，始终纳入；已关闭的 Issue 仅在当天关闭时纳入。
-func issueWorkedSince(issue *gh.Issue, since time.Time) bool {
-	if issue.GetState() == "open" {
+// issueWorkedSince 判断 Issue 是否应纳入 [since, until] 时间范围内的工作。
+// until 时仍处于 open 状态的 Issue 视为进行中的工作，始终纳入；已关闭的 Issue 仅在时间范围内创建或关闭时纳入。
+// 在 until 之后才创建的 Issue 不纳入。
+func issueWorkedSince(issue *gh.Issue, since, until time.Time) bool {
+	if issue.GetCreatedAt().After(until) {
+		return false
+	}
+	if issueStateAt(issue, until) == "open" {
 		return true
 	}
 	if !issue.GetCreatedAt().Before(since) {
@@ -0,0 +1,273 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// MemberSummary 保存团队中单个成员的工作和计划条目。
+type MemberSummary struct {
+	User      string
+	WorkItems []WorkItem
+	PlanItems []PlanItem
+}
+
+// buildMemberSummaries 对每个成员分别应用 extractItems 规则。
+// reports 只需收集一次，成员之间共享。
+func buildMemberSummaries(reports []RepoReport, members []string, since, until time.Time, rt ReportType) []MemberSummary {
+	summaries := make([]MemberSummary, 0, len(members))
+	for _, m := range members {
+		work, plan := extractItems(reports, m, since, until, rt)
+		summaries = append(summaries, MemberSummary{
+			User:      m,
+			WorkItems: work,
+			PlanItems: plan,
+		})
+	}
+	return summaries
+}
+
+// countWorkItems 按类型统计工作条目数量。
+func countWorkItems(items []WorkItem) map[string]int {
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[item.Type]++
+	}
+	return counts
+}
+
+// formatTeamOverview 将团队概览格式化为对齐的文本表格，每个成员一行，末尾为合计行。
+// 表头使用 ASCII 文本，避免中文宽字符导致 tabwriter 对齐错位。
+func formatTeamOverview(summaries []MemberSummary) string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Member\tPR\tIssue\tComment\tReview\tCommit\tPlan")
+
+	var total [6]int
+	for _, s := range summaries {
+		counts := countWorkItems(s.WorkItems)
+		row := [6]int{counts["pr"], counts["issue"], counts["comment"], counts["review"], counts["commit"], len(s.PlanItems)}
+		for i, v := range row {
+			total[i] += v
+		}
+		fmt.Fprintf(tw, "@%s\t%d\t%d\t%d\t%d\t%d\t%d\n", s.User, row[0], row[1], row[2], row[3], row[4], row[5])
+	}
+	fmt.Fprintf(tw, "Total\t%d\t%d\t%d\t%d\t%d\t%d\n", total[0], total[1], total[2], total[3], total[4], total[5])
+	tw.Flush()
+	return sb.String()
+}
+
+// PrintTeamSummaryData 输出团队模式的结构化数据：团队概览 + 每个成员的工作和计划条目，
+// 以及可供手动粘贴给 AI 的 Prompt 模板。
+func PrintTeamSummaryData(w io.Writer, reports []RepoReport, since, until time.Time, members []string, rt ReportType) {
+	labels := labelsForType(rt)
+	summaries := buildMemberSummaries(reports, members, since, until, rt)
+
+	fmt.Fprintln(w, "========== 团队概览 ==========")
+	fmt.Fprint(w, formatTeamOverview(summaries))
+	fmt.Fprintln(w)
+
+	for _, s := range summaries {
+		fmt.Fprintf(w, "========== @%s ==========\n", s.User)
+		fmt.Fprintf(w, "--- %s ---\n", labels.workTitle)
+		fmt.Fprint(w, formatWorkData(s.WorkItems, rt))
+		fmt.Fprintf(w, "--- %s ---\n", labels.planTitle)
+		fmt.Fprint(w, formatPlanData(s.PlanItems))
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "========== Prompt（复制以下内容粘贴给 AI）==========")
+	fmt.Fprintln(w)
+	fmt.Fprint(w, buildTeamPromptFromSummaries(summaries, since, until, rt))
+}
+
+// BuildTeamSummaryPrompt 构建团队模式的完整 Prompt 文本，供 API 调用或手动粘贴。
+func BuildTeamSummaryPrompt(reports []RepoReport, since, until time.Time, members []string, rt ReportType) string {
+	summaries := buildMemberSummaries(reports, members, since, until, rt)
+	return buildTeamPromptFromSummaries(summaries, since, until, rt)
+}
+
+// PlanTeamSummary 构建团队模式 AI 报告的生成计划。完整 Prompt 的估算 token 数不超过 maxTokens（<= 0 时使用
+// DefaultMaxPromptTokens）时不分段；否则逐个成员按 PlanSummary 的规则分段（跨月时按月份，否则按仓库），
+// 分段说明带成员前缀（如 "@alice 2026-09"）。合并时提供团队概览和每个成员的计划条目。
+func PlanTeamSummary(reports []RepoReport, since, until time.Time, members []string, rt ReportType, maxTokens int) SummaryPlan {
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxPromptTokens
+	}
+	summaries := buildMemberSummaries(reports, members, since, until, rt)
+	plan := SummaryPlan{Prompt: buildTeamPromptFromSummaries(summaries, since, until, rt)}
+	if EstimateTokens(plan.Prompt) <= maxTokens {
+		return plan
+	}
+
+	byMonth := since.Format("2006-01") != until.Format("2006-01")
+	type memberChunk struct {
+		user string
+		workGroup
+	}
+	var chunks []memberChunk
+	for _, s := range summaries {
+		budget := maxTokens - EstimateTokens(buildChunkPromptTemplate(since, until, s.User, rt, "@"+s.User, 0, 0))
+		for _, c := range packWorkGroups(groupWorkItems(s.WorkItems, byMonth), rt, budget) {
+			chunks = append(chunks, memberChunk{user: s.User, workGroup: workGroup{label: "@" + s.User + " " + c.label, items: c.items}})
+		}
+	}
+	if len(chunks) == 0 {
+		return plan
+	}
+
+	plan.Chunks = make([]SummaryChunk, len(chunks))
+	for i, c := range chunks {
+		var sb strings.Builder
+		sb.WriteString(buildChunkPromptTemplate(since, until, c.user, rt, c.label, i+1, len(chunks)))
+		sb.WriteString(formatWorkData(c.items, rt))
+		plan.Chunks[i] = SummaryChunk{Label: c.label, Prompt: sb.String()}
+	}
+	groupBy := "成员和仓库"
+	if byMonth {
+		groupBy = "成员和月份"
+	}
+	plan.maxTokens = maxTokens
+	plan.final = func(parts []summaryPart) string {
+		return buildTeamMergePrompt(summaries, parts, since, until, rt, groupBy)
+	}
+	plan.regroup = func(label string, index, total int) string {
+		return buildRegroupPromptTemplate(since, until, strings.Join(teamLogins(summaries), ", "), rt, true, label, index, total)
+	}
+	return plan
+}
+
+// teamLogins 返回成员的展示名（带 @ 前缀）。
+func teamLogins(summaries []MemberSummary) []string {
+	logins := make([]string, len(summaries))
+	for i, s := range summaries {
+		logins[i] = "@" + s.User
+	}
+	return logins
+}
+
+// buildTeamPromptFromSummaries 根据成员条目构建团队 Prompt：指令模板 + 团队概览 + 按成员分节的数据。
+func buildTeamPromptFromSummaries(summaries []MemberSummary, since, until time.Time, rt ReportType) string {
+	labels := labelsForType(rt)
+
+	var sb strings.Builder
+	sb.WriteString(buildTeamPromptTemplate(summaries, since, until, rt))
+	sb.WriteString("\n以下是活动数据:\n\n")
+
+	sb.WriteString("=== 团队概览 ===\n")
+	sb.WriteString(formatTeamOverview(summaries))
+	for _, s := range summaries {
+		fmt.Fprintf(&sb, "\n=== @%s %s数据 ===\n", s.User, labels.workTitle)
+		sb.WriteString(formatWorkData(s.WorkItems, rt))
+		fmt.Fprintf(&sb, "=== @%s %s数据 ===\n", s.User, labels.planTitle)
+		sb.WriteString(formatPlanData(s.PlanItems))
+	}
+
+	return sb.String()
+}
+
+// buildTeamMergePrompt 构建团队模式合并阶段的 Prompt：团队指令模板 + 团队概览 + 各段整理后的工作条目
+// （分段说明带成员前缀）+ 每个成员的计划数据。
+func buildTeamMergePrompt(summaries []MemberSummary, parts []summaryPart, since, until time.Time, rt ReportType, groupBy string) string {
+	labels := labelsForType(rt)
+
+	var sb strings.Builder
+	sb.WriteString(buildTeamPromptTemplate(summaries, since, until, rt))
+	sb.WriteString("\n以下是活动数据:\n\n")
+
+	sb.WriteString("=== 团队概览 ===\n")
+	sb.WriteString(formatTeamOverview(summaries))
+	fmt.Fprintf(&sb, "\n=== %s数据（已按%s分 %d 段整理）===\n", labels.workTitle, groupBy, len(parts))
+	sb.WriteString("以下工作条目已按分段整理为 \"<日期> <工作描述>, <状态>, <URL>\" 格式，分段说明中的 @<成员> 表示条目所属成员")
+	sb.WriteString("（合并多个成员的分段中，条目按 \"@<成员>\" 分节）。")
+	sb.WriteString("请合并为最终报告：去除各段之间重复的条目（同一 URL 保留最近的状态），按上述格式要求输出。\n")
+	writeSummaryParts(&sb, parts)
+	for _, s := range summaries {
+		fmt.Fprintf(&sb, "\n=== @%s %s数据 ===\n", s.User, labels.planTitle)
+		sb.WriteString(formatPlanData(s.PlanItems))
+	}
+
+	return sb.String()
+}
+
+// buildTeamPromptTemplate 构建团队 Prompt 的指令模板，根据报告类型调整措辞。
+func buildTeamPromptTemplate(summaries []MemberSummary, since, until time.Time, rt ReportType) string {
+	labels := labelsForType(rt)
+	dateRange := fmt.Sprintf("%s ~ %s", since.Format("2006-01-02"), until.Format("2006-01-02"))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "你是一个团队%s。请根据以下团队成员的活动数据，生成团队%s。\n\n", labels.roleName, labels.reportName)
+	fmt.Fprintf(&sb, "日期范围: %s\n", dateRange)
+	fmt.Fprintf(&sb, "成员: %s\n\n", strings.Join(teamLogins(summaries), ", "))
+	sb.WriteString("请严格按照以下格式输出，不要添加任何额外内容:\n\n")
+	sb.WriteString("团队概览\n")
+	sb.WriteString("<2~3 句话概括团队整体进展>\n\n")
+	sb.WriteString("@<成员>\n")
+	fmt.Fprintf(&sb, "%s\n", labels.workTitle)
+	sb.WriteString("<工作描述>, <状态>, <URL>\n")
+	fmt.Fprintf(&sb, "%s\n", labels.planTitle)
+	sb.WriteString("<计划描述>, <URL>\n\n")
+	sb.WriteString("格式要求:\n")
+	sb.WriteString("- 每个成员一节，按成员数据的顺序输出，没有任何条目的成员也要保留标题并写\"无\"\n")
+	sb.WriteString("- 每条记录一行\n")
+	sb.WriteString(workStatePromptRules)
+	fmt.Fprintf(&sb, reviewRequestPromptRule, "该成员")
+	if labels.workDesc != "" {
+		fmt.Fprintf(&sb, "- %s\n", labels.workDesc)
+	}
+	fmt.Fprintf(&sb, "- %s\n", labels.planDesc)
+	fmt.Fprintf(&sb, "- %s\n", labels.noPlanStatus)
+	if labels.dateHint != "" {
+		fmt.Fprintf(&sb, "- %s\n", labels.dateHint)
+	}
+
+	return sb.String()
+}
+
+// PrintTeamJSON 以 JSON 格式输出团队报告：与 PrintJSON 相同的仓库数据，另加 members 数组，
+// 按成员顺序给出每个成员的工作和计划条目。
+func PrintTeamJSON(w io.Writer, reports []RepoReport, since, until time.Time, team string, members []string, rt ReportType) error {
+	out := buildJSONReport(reports, since, until, team, rt)
+	for _, s := range buildMemberSummaries(reports, members, since, until, rt) {
+		out.Members = append(out.Members, toJSONMember(s))
+	}
+	return writeJSON(w, out)
+}
+
+// PrintTeamHTML 以 HTML 格式输出团队报告：团队概览表格和每个成员的工作、计划条目，其后为各仓库的活动数据。
+func PrintTeamHTML(w io.Writer, reports []RepoReport, since, until time.Time, team string, members []string, rt ReportType) error {
+	page := buildHTMLPage(reports, since, until, team, rt)
+	for _, s := range buildMemberSummaries(reports, members, since, until, rt) {
+		page.Members = append(page.Members, buildHTMLMember(s))
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+// PrintTeamMarkdown 以 Markdown 格式输出团队报告：团队概览表格 + 每个成员一节。
+func PrintTeamMarkdown(w io.Writer, reports []RepoReport, since, until time.Time, members []string, rt ReportType) {
+	labels := labelsForType(rt)
+	summaries := buildMemberSummaries(reports, members, since, until, rt)
+
+	fmt.Fprintf(w, "# 团队%s（%s ~ %s）\n\n", labels.reportName, since.Format("2006-01-02"), until.Format("2006-01-02"))
+
+	fmt.Fprint(w, "## 团队概览\n\n")
+	fmt.Fprintln(w, "| 成员 | PR | Issue | 评论 | Review | 提交 | 计划 |")
+	fmt.Fprintln(w, "|------|----|-------|------|--------|------|------|")
+	for _, s := range summaries {
+		counts := countWorkItems(s.WorkItems)
+		fmt.Fprintf(w, "| @%s | %d | %d | %d | %d | %d | %d |\n",
+			s.User, counts["pr"], counts["issue"], counts["comment"], counts["review"], counts["commit"], len(s.PlanItems))
+	}
+	fmt.Fprintln(w)
+
+	for _, s := range summaries {
+		fmt.Fprintf(w, "## @%s\n\n", s.User)
+		fmt.Fprintf(w, "**%s**\n\n", labels.workTitle)
+		writeMarkdownWork(w, s.WorkItems, rt)
+		fmt.Fprintf(w, "**%s**\n\n", labels.planTitle)
+		writeMarkdownPlan(w, s.PlanItems)
+	}
+}
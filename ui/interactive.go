@@ -29,18 +29,25 @@ var reportTypeOptions = []huh.Option[string]{
 	huh.NewOption("周报（最近 14 天）", "weekly"),
 	huh.NewOption("月报（最近 60 天）", "monthly"),
 	huh.NewOption("年报（最近 730 天）", "yearly"),
+	huh.NewOption("迭代报告（Projects v2 当前迭代）", "iteration"),
 }
 
 // 输出格式选项
 var formatOptions = []huh.Option[string]{
 	huh.NewOption("CSV（原始数据）", "csv"),
 	huh.NewOption("Summary（结构化摘要）", "summary"),
+	huh.NewOption("Markdown（可粘贴到 Wiki）", "markdown"),
+	huh.NewOption("HTML（离线网页）", "html"),
+	huh.NewOption("JSON（结构化数据）", "json"),
 }
 
 // AI 提供商选项
 var aiProviderOptions = []huh.Option[string]{
 	huh.NewOption("Anthropic Claude", "anthropic"),
 	huh.NewOption("OpenAI", "openai"),
+	huh.NewOption("Azure OpenAI（需配置 AZURE_OPENAI_* 环境变量）", "azure-openai"),
+	huh.NewOption("Google Gemini", "gemini"),
+	huh.NewOption("Ollama（本地模型）", "ollama"),
 }
 
 
@@ -12,6 +12,8 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/term"
+
+	"github.com/miclle/gh-report/github"
 )
 
 // 样式定义
@@ -19,6 +21,8 @@ var (
 	repoNameStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
 	barFillStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("10")) // 绿色
 	barEmptyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))  // 灰色
+	quotaStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))  // 灰色
+	waitStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("11")) // 黄色
 )
 
 const barWidth = 50
@@ -36,13 +40,17 @@ type Progress struct {
 	mu         sync.Mutex
 	rendered   bool
 	isTerm     bool
+	rate       github.RateLimit
+	hasRate    bool
+	sigChan    chan os.Signal
+	done       chan struct{}
 }
 
 // NewProgress 创建新的进度显示组件。
 func NewProgress(repos []string) *Progress {
 	progresses := make([]repoProgress, len(repos))
 	for i := range repos {
-		progresses[i] = repoProgress{total: 6, current: 0}
+		progresses[i] = repoProgress{total: 7, current: 0}
 	}
 
 	return &Progress{
@@ -52,6 +60,18 @@ func NewProgress(repos []string) *Progress {
 	}
 }
 
+// SetRepos 替换进度显示的仓库列表，需在 Start 之前调用。
+func (p *Progress) SetRepos(repos []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.repos = repos
+	p.progresses = make([]repoProgress, len(repos))
+	for i := range repos {
+		p.progresses[i] = repoProgress{total: 7, current: 0}
+	}
+}
+
 // SetTotal 设置指定仓库的总步骤数。
 func (p *Progress) SetTotal(repoIndex int, total int) {
 	p.mu.Lock()
@@ -74,6 +94,23 @@ func (p *Progress) Increment(repoIndex int) {
 	}
 }
 
+// SetRateLimit 更新 GitHub API 配额状态，显示在进度区域的标题行。
+// 可作为 github.WithRateLimitObserver 的回调使用。
+func (p *Progress) SetRateLimit(rate github.RateLimit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rate = rate
+	p.hasRate = true
+	if !p.isTerm && !rate.RetryAt.IsZero() {
+		fmt.Fprintf(os.Stderr, "触发 GitHub API 限流，等待至 %s 后重试\n", rate.RetryAt.Format("15:04:05"))
+		return
+	}
+	if p.rendered {
+		p.render()
+	}
+}
+
 // SetError 设置错误状态。
 func (p *Progress) SetError(err error) {
 	// 不需要额外处理
@@ -94,14 +131,18 @@ func (p *Progress) Start() *ProgressWrapper {
 		return &ProgressWrapper{p}
 	}
 
-	// 注册信号处理，确保 Ctrl+C 时恢复光标
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		p.Stop()
-		os.Exit(1)
-	}()
+	// 注册信号处理，确保 Ctrl+C 时恢复光标；Stop 时注销，之后的信号由调用方处理
+	p.sigChan = make(chan os.Signal, 1)
+	p.done = make(chan struct{})
+	signal.Notify(p.sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func(sigChan chan os.Signal, done chan struct{}) {
+		select {
+		case <-sigChan:
+			p.Stop()
+			os.Exit(1)
+		case <-done:
+		}
+	}(p.sigChan, p.done)
 
 	// 隐藏光标
 	fmt.Fprint(os.Stderr, "\033[?25l")
@@ -149,7 +190,12 @@ func (p *Progress) render() {
 
 	// 构建输出
 	var b strings.Builder
-	b.WriteString("正在获取 GitHub 数据...\n")
+	b.WriteString("正在获取 GitHub 数据...")
+	if p.hasRate {
+		b.WriteString("  ")
+		b.WriteString(p.rateStatus())
+	}
+	b.WriteString("\033[K\n")
 
 	for i, repo := range p.repos {
 		pr := p.progresses[i]
@@ -169,17 +215,39 @@ func (p *Progress) render() {
 	fmt.Fprint(os.Stderr, b.String())
 }
 
+// rateStatus 返回配额状态文本：剩余配额和重置时间，触发限流时显示重试时间。
+func (p *Progress) rateStatus() string {
+	if time.Now().Before(p.rate.RetryAt) {
+		return waitStyle.Render(fmt.Sprintf("触发限流，等待至 %s 后重试", p.rate.RetryAt.Format("15:04:05")))
+	}
+	if p.rate.Limit == 0 {
+		return ""
+	}
+	status := fmt.Sprintf("API 配额: %d/%d", p.rate.Remaining, p.rate.Limit)
+	if !p.rate.Reset.IsZero() {
+		status += fmt.Sprintf("（%s 重置）", p.rate.Reset.Format("15:04"))
+	}
+	return quotaStyle.Render(status)
+}
+
 // Stop 停止进度显示。
 func (p *Progress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if !p.isTerm || !p.rendered {
 		return
 	}
 
+	signal.Stop(p.sigChan)
+	close(p.done)
+
 	// 显示光标
 	fmt.Fprint(os.Stderr, "\033[?25h")
 
 	// 清除进度条区域并添加空行分隔
 	fmt.Fprint(os.Stderr, "\r\033[J\n")
+	p.rendered = false
 }
 
 // ProgressWrapper 包装 Progress 实现 report.Progress 接口。
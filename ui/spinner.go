@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -61,8 +63,9 @@ func (m *SpinnerModel) View() string {
 }
 
 // Start 启动 spinner。
+// spinner 只负责输出，不读取终端输入也不处理信号，Ctrl-C 由调用方（通过 context）处理。
 func (m *SpinnerModel) Start() {
-	m.program = tea.NewProgram(m, tea.WithOutput(os.Stderr))
+	m.program = tea.NewProgram(m, tea.WithOutput(os.Stderr), tea.WithInput(nil), tea.WithoutSignalHandler())
 	go func() {
 		_, _ = m.program.Run()
 		close(m.doneChan)
@@ -102,6 +105,27 @@ func RunSpinnerWithResult[T any](text string, action func() (T, error)) (T, erro
 	return result, nil
 }
 
+// StreamWithSpinner 运行 spinner 并执行流式操作：收到第一段文本前显示 spinner，
+// 之后停止 spinner 并将文本实时写入 w。返回完整文本，输出的文本末尾保证有换行。
+func StreamWithSpinner(text string, w io.Writer, action func(onDelta func(string)) (string, error)) (string, error) {
+	s := NewSpinner(text)
+	s.Start()
+	var stopOnce sync.Once
+	stop := func() { stopOnce.Do(s.Stop) }
+	defer stop()
+
+	var last string
+	result, err := action(func(delta string) {
+		stop()
+		fmt.Fprint(w, delta)
+		last = delta
+	})
+	if last != "" && !strings.HasSuffix(last, "\n") {
+		fmt.Fprintln(w)
+	}
+	return result, err
+}
+
 // PrintSuccess 打印成功消息。
 func PrintSuccess(msg string) {
 	style := lipgloss.NewStyle().